@@ -0,0 +1,223 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+const (
+	AUTO_COMPLETE_DEFAULT_ROWS = 6  // Rows shown in the popup. More can be scrolled to with the arrow keys
+	AUTO_COMPLETE_MAX_ITEMS    = 50 // Provider results after this are ignored
+)
+
+/*
+A widget that draws a popup outside of its own rect. The sub group checks it when finding the widget at x,y
+*/
+type sdl_Popup interface {
+	insidePopup(x, y int32) bool
+}
+
+/****************************************************************************************
+* sdl_AutoComplete code
+* The suggestions popup for an SDL_Entry. The provider is called with the text before the cursor
+* and returns the suggestions. Suggestions equal to the prefix are ignored.
+* Also used (with no provider) for the paste history popup.
+**/
+type sdl_AutoComplete struct {
+	provider func(string) []string
+	prefix   string
+	items    []string
+	index    int // The selected item. -1 if none
+	top      int // The first item shown in the popup
+	rows     int
+}
+
+func newAutoComplete(provider func(string) []string) *sdl_AutoComplete {
+	return &sdl_AutoComplete{provider: provider, index: -1, rows: AUTO_COMPLETE_DEFAULT_ROWS}
+}
+
+func (ac *sdl_AutoComplete) update(prefix string) {
+	ac.prefix = prefix
+	ac.items = nil
+	ac.index = -1
+	ac.top = 0
+	if prefix == "" {
+		return
+	}
+	for _, s := range ac.provider(prefix) {
+		if s != prefix {
+			ac.items = append(ac.items, s)
+			if len(ac.items) >= AUTO_COMPLETE_MAX_ITEMS {
+				break
+			}
+		}
+	}
+}
+
+/*
+Show a fixed list with the first item selected. Used for the paste history.
+*/
+func (ac *sdl_AutoComplete) show(items []string) {
+	ac.close()
+	ac.prefix = ""
+	for _, s := range items {
+		ac.items = append(ac.items, s)
+		if len(ac.items) >= AUTO_COMPLETE_MAX_ITEMS {
+			break
+		}
+	}
+	if len(ac.items) > 0 {
+		ac.index = 0
+	}
+}
+
+func (ac *sdl_AutoComplete) close() {
+	ac.items = nil
+	ac.index = -1
+	ac.top = 0
+}
+
+func (ac *sdl_AutoComplete) isOpen() bool {
+	return len(ac.items) > 0
+}
+
+/*
+Move the selection up (-1) or down (1) and scroll so it is visible.
+*/
+func (ac *sdl_AutoComplete) move(d int) {
+	if !ac.isOpen() {
+		return
+	}
+	ac.index = ac.index + d
+	if ac.index < 0 {
+		ac.index = 0
+	}
+	if ac.index >= len(ac.items) {
+		ac.index = len(ac.items) - 1
+	}
+	if ac.index < ac.top {
+		ac.top = ac.index
+	}
+	if ac.index >= ac.top+ac.rows {
+		ac.top = ac.index - ac.rows + 1
+	}
+}
+
+/*
+The selected item or the first if none are selected. "" if there are no items.
+*/
+func (ac *sdl_AutoComplete) selected() string {
+	if !ac.isOpen() {
+		return ""
+	}
+	if ac.index < 0 {
+		return ac.items[0]
+	}
+	return ac.items[ac.index]
+}
+
+/*
+The rest of the selected item if it starts with the prefix. Drawn dimmed after the text.
+*/
+func (ac *sdl_AutoComplete) ghost() string {
+	s := ac.selected()
+	if strings.HasPrefix(s, ac.prefix) {
+		return s[len(ac.prefix):]
+	}
+	return ""
+}
+
+func (ac *sdl_AutoComplete) visibleRows() int {
+	n := len(ac.items) - ac.top
+	if n > ac.rows {
+		return ac.rows
+	}
+	return n
+}
+
+/*
+The popup is drawn under the entry. Each row is rowH high.
+*/
+func (ac *sdl_AutoComplete) rect(x, y, w, rowH int32) *sdl.Rect {
+	return &sdl.Rect{X: x, Y: y, W: w, H: int32(ac.visibleRows()) * rowH}
+}
+
+/*
+The index of the item at screen y. -1 if not on an item
+*/
+func (ac *sdl_AutoComplete) itemAt(r *sdl.Rect, rowH, x, y int32) int {
+	if !ac.isOpen() || !isInsideRect(x, y, r) {
+		return -1
+	}
+	i := ac.top + int((y-r.Y)/rowH)
+	if i >= len(ac.items) {
+		return -1
+	}
+	return i
+}
+
+func (ac *sdl_AutoComplete) draw(renderer *sdl.Renderer, font *ttf.Font, r *sdl.Rect, rowH, id int32, fg, bg, border *sdl.Color) {
+	renderer.SetDrawColor(bg.R, bg.G, bg.B, bg.A)
+	renderer.FillRect(r)
+	tm := rowH / 9
+	for row := 0; row < ac.visibleRows(); row++ {
+		i := ac.top + row
+		ry := r.Y + int32(row)*rowH
+		if i == ac.index {
+			c := GetResourceInstance().GetCursorSelectColour()
+			renderer.SetDrawColor(c.R, c.G, c.B, c.A)
+			renderer.FillRect(&sdl.Rect{X: r.X, Y: ry, W: r.W, H: rowH})
+		}
+		ct, err := GetResourceInstance().UpdateTextureFromString(renderer, fmt.Sprintf("entry:%d:suggest:%d", id, row), ac.items[i], font, fg)
+		if err != nil {
+			continue
+		}
+		rw, sw, sh := ct.ScaledWidthHeight(rowH-(2*tm), r.W-10)
+		renderer.Copy(ct.texture, &sdl.Rect{X: 0, Y: 0, W: rw, H: ct.h}, &sdl.Rect{X: r.X + 5, Y: ry + tm, W: sw, H: sh})
+	}
+	renderer.SetDrawColor(border.R, border.G, border.B, border.A)
+	renderer.DrawRect(r)
+}
+
+/*
+Returns an auto complete provider for file system paths. The text in the entry is a path. Suggestions are
+the files and dirs in its dir that start with its last element. Dirs end with a path separator so accepting
+one goes on to suggest its contents. filter is the same as SDL_FileList. It can be nil.
+Hidden files (starting with '.') are only suggested if the last element starts with '.'.
+*/
+func PathAutoCompleteProvider(filter func(bool, string) bool) func(string) []string {
+	return func(prefix string) []string {
+		dir, base := filepath.Split(prefix)
+		readDir := dir
+		if readDir == "" {
+			readDir = "."
+		}
+		list, err := ioutil.ReadDir(readDir)
+		if err != nil {
+			return nil
+		}
+		dirs := make([]string, 0)
+		files := make([]string, 0)
+		for _, fil := range list {
+			name := fil.Name()
+			if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+				continue
+			}
+			if filter != nil && !filter(fil.IsDir(), name) {
+				continue
+			}
+			if fil.IsDir() {
+				dirs = append(dirs, dir+name+string(os.PathSeparator))
+			} else {
+				files = append(files, dir+name)
+			}
+		}
+		return append(dirs, files...)
+	}
+}
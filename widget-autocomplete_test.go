@@ -0,0 +1,107 @@
+package go_sdl_widget
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var testWords = []string{"apple", "apricot", "banana", "Apex"}
+
+func testWordProvider(prefix string) []string {
+	l := make([]string, 0)
+	for _, w := range testWords {
+		if strings.HasPrefix(w, prefix) {
+			l = append(l, w)
+		}
+	}
+	return l
+}
+
+func TestEntryAutoComplete(t *testing.T) {
+	e := NewSDLEntry(10, 10, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetAutoComplete(testWordProvider)
+	e.SetFocused(true)
+	typeString(e, "ap")
+	assertInt(t, "Suggestions", len(e.GetSuggestions()), 2)
+	assertString(t, "Ghost", e.autoComplete.ghost(), "ple")
+	e.KeyPress(sdl.K_DOWN, true, true)
+	e.KeyPress(sdl.K_DOWN, true, true)
+	assertString(t, "Down", e.autoComplete.ghost(), "ricot")
+	e.KeyPress(sdl.K_DOWN, true, true)
+	assertString(t, "Down stops at end", e.autoComplete.selected(), "apricot")
+	e.KeyPress(sdl.K_UP, true, true)
+	e.KeyPress(sdl.K_RETURN, true, true)
+	assertString(t, "Enter accepts", e.GetText(), "apple")
+	assertInt(t, "Cursor at end", e.cursor, 5)
+	assertBool(t, "Closed", "IsSuggesting", e.IsSuggesting(), false)
+
+	e.SetText("")
+	e.SetCursor(0)
+	e.SetMaxLength(4)
+	typeString(e, "ban")
+	e.KeyPress(sdl.K_RETURN, true, true)
+	assertString(t, "Accept cut at max length", e.GetText(), "bana")
+	e.SetMaxLength(0)
+
+	e.SetText("")
+	e.SetCursor(0)
+	typeString(e, "b")
+	assertBool(t, "Tab", "KeyPress", e.KeyPress(sdl.K_TAB, true, true), true)
+	assertString(t, "Tab accepts first", e.GetText(), "banana")
+	assertBool(t, "Tab not suggesting", "KeyPress", e.KeyPress(sdl.K_TAB, true, true), false)
+
+	typeString(e, "x")
+	assertBool(t, "No match", "IsSuggesting", e.IsSuggesting(), false)
+	e.SetText("")
+	e.SetCursor(0)
+	typeString(e, "a")
+	e.KeyPress(sdl.K_ESCAPE, true, true)
+	assertBool(t, "Escape", "IsSuggesting", e.IsSuggesting(), false)
+	assertString(t, "Escape keeps text", e.GetText(), "a")
+
+	typeString(e, "p")
+	assertBool(t, "Inside popup", "insidePopup", e.insidePopup(20, 35), true)
+	md := &SDL_MouseData{x: 20, y: 55}
+	assertBool(t, "Click", "Click", e.Click(md), true)
+	assertString(t, "Click accepts", e.GetText(), "apricot")
+
+	typeString(e, "ap")
+	e.SetFocused(false)
+	assertBool(t, "Un focus", "IsSuggesting", e.IsSuggesting(), false)
+}
+
+func TestPathAutoCompleteProvider(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "docs"), 0755)
+	os.WriteFile(filepath.Join(dir, "data.txt"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "dog.png"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, ".dot"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "docs", "readme"), []byte("x"), 0644)
+
+	sep := string(os.PathSeparator)
+	p := PathAutoCompleteProvider(nil)
+	l := p(dir + sep + "d")
+	assertInt(t, "Matches", len(l), 3)
+	assertString(t, "Dirs first", l[0], dir+sep+"docs"+sep)
+	assertString(t, "Then files", l[1], dir+sep+"data.txt")
+	assertInt(t, "Not hidden", len(p(dir+sep)), 3)
+	assertInt(t, "Hidden prefix", len(p(dir+sep+".")), 1)
+	assertInt(t, "Bad dir", len(p(dir+sep+"nothere"+sep)), 0)
+
+	p = PathAutoCompleteProvider(func(isDir bool, name string) bool {
+		return isDir || strings.HasSuffix(name, ".png")
+	})
+	assertInt(t, "Filter", len(p(dir+sep+"d")), 2)
+
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetAutoComplete(PathAutoCompleteProvider(nil))
+	e.SetFocused(true)
+	typeString(e, dir+sep+"doc")
+	e.KeyPress(sdl.K_TAB, true, true)
+	assertString(t, "Accept dir", e.GetText(), dir+sep+"docs"+sep)
+	assertString(t, "Dir contents", strings.Join(e.GetSuggestions(), ","), dir+sep+"docs"+sep+"readme")
+}
@@ -0,0 +1,664 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/veandco/go-sdl2/gfx"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+const (
+	canvas_HIT_TOLERANCE int32 = 3 // How close (in pixels) a point must be to a line to hit it
+)
+
+/****************************************************************************************
+* SDL_CanvasPrimitive
+* A retained drawing primitive held by an SDL_Canvas.
+* All coordinates are local to the canvas. 0,0 is the top left of the canvas.
+**/
+type SDL_CanvasPrimitive interface {
+	GetId() int32
+	Draw(*sdl.Renderer, *ttf.Font)
+	Inside(int32, int32) bool
+	Move(int32, int32)
+	Scale(float32)
+	SetColour(*sdl.Color)
+	GetColour() *sdl.Color
+	SetVisible(bool)
+	IsVisible() bool
+}
+
+type sdl_CanvasPrimitiveBase struct {
+	id      int32
+	colour  *sdl.Color
+	filled  bool
+	visible bool
+}
+
+func (p *sdl_CanvasPrimitiveBase) GetId() int32 {
+	return p.id
+}
+
+func (p *sdl_CanvasPrimitiveBase) SetColour(c *sdl.Color) {
+	p.colour = c
+}
+
+func (p *sdl_CanvasPrimitiveBase) GetColour() *sdl.Color {
+	if p.colour == nil {
+		return GetResourceInstance().GetColour(WIDGET_COLOUR_INDEX_ENABLED, WIDGET_COLOUR_STYLE_FG)
+	}
+	return p.colour
+}
+
+func (p *sdl_CanvasPrimitiveBase) SetVisible(v bool) {
+	p.visible = v
+}
+
+func (p *sdl_CanvasPrimitiveBase) IsVisible() bool {
+	return p.visible
+}
+
+func (p *sdl_CanvasPrimitiveBase) SetFilled(f bool) {
+	p.filled = f
+}
+
+func (p *sdl_CanvasPrimitiveBase) IsFilled() bool {
+	return p.filled
+}
+
+/****************************************************************************************
+* SDL_CanvasLine code
+**/
+type SDL_CanvasLine struct {
+	sdl_CanvasPrimitiveBase
+	x1, y1, x2, y2 int32
+	width          int32
+}
+
+func (l *SDL_CanvasLine) SetPoints(x1, y1, x2, y2 int32) {
+	l.x1, l.y1, l.x2, l.y2 = x1, y1, x2, y2
+}
+
+func (l *SDL_CanvasLine) SetWidth(w int32) {
+	l.width = w
+}
+
+func (l *SDL_CanvasLine) Draw(renderer *sdl.Renderer, font *ttf.Font) {
+	if l.width > 1 {
+		gfx.ThickLineColor(renderer, l.x1, l.y1, l.x2, l.y2, l.width, *l.GetColour())
+	} else {
+		gfx.LineColor(renderer, l.x1, l.y1, l.x2, l.y2, *l.GetColour())
+	}
+}
+
+func (l *SDL_CanvasLine) Inside(x, y int32) bool {
+	tol := float64(canvas_HIT_TOLERANCE + (l.width / 2))
+	return canvasDistToSegmentSq(float64(x), float64(y), float64(l.x1), float64(l.y1), float64(l.x2), float64(l.y2)) <= tol*tol
+}
+
+func (l *SDL_CanvasLine) Move(dx, dy int32) {
+	l.x1, l.y1, l.x2, l.y2 = l.x1+dx, l.y1+dy, l.x2+dx, l.y2+dy
+}
+
+func (l *SDL_CanvasLine) Scale(s float32) {
+	l.x1, l.y1 = scaleInt32(l.x1, s), scaleInt32(l.y1, s)
+	l.x2, l.y2 = scaleInt32(l.x2, s), scaleInt32(l.y2, s)
+	l.width = scaleInt32(l.width, s)
+}
+
+/****************************************************************************************
+* SDL_CanvasRect code
+**/
+type SDL_CanvasRect struct {
+	sdl_CanvasPrimitiveBase
+	rect sdl.Rect
+}
+
+func (r *SDL_CanvasRect) SetRect(rect *sdl.Rect) {
+	r.rect = *rect
+}
+
+func (r *SDL_CanvasRect) GetRect() *sdl.Rect {
+	return &sdl.Rect{X: r.rect.X, Y: r.rect.Y, W: r.rect.W, H: r.rect.H}
+}
+
+func (r *SDL_CanvasRect) Draw(renderer *sdl.Renderer, font *ttf.Font) {
+	c := r.GetColour()
+	renderer.SetDrawColor(c.R, c.G, c.B, c.A)
+	if r.filled {
+		renderer.FillRect(&r.rect)
+	} else {
+		renderer.DrawRect(&r.rect)
+	}
+}
+
+func (r *SDL_CanvasRect) Inside(x, y int32) bool {
+	return isInsideRect(x, y, &r.rect)
+}
+
+func (r *SDL_CanvasRect) Move(dx, dy int32) {
+	r.rect.X = r.rect.X + dx
+	r.rect.Y = r.rect.Y + dy
+}
+
+func (r *SDL_CanvasRect) Scale(s float32) {
+	r.rect = sdl.Rect{X: scaleInt32(r.rect.X, s), Y: scaleInt32(r.rect.Y, s), W: scaleInt32(r.rect.W, s), H: scaleInt32(r.rect.H, s)}
+}
+
+/****************************************************************************************
+* SDL_CanvasCircle code
+**/
+type SDL_CanvasCircle struct {
+	sdl_CanvasPrimitiveBase
+	cx, cy, rad int32
+}
+
+func (c *SDL_CanvasCircle) SetCentre(cx, cy int32) {
+	c.cx, c.cy = cx, cy
+}
+
+func (c *SDL_CanvasCircle) SetRadius(rad int32) {
+	c.rad = rad
+}
+
+func (c *SDL_CanvasCircle) Draw(renderer *sdl.Renderer, font *ttf.Font) {
+	if c.filled {
+		gfx.FilledCircleColor(renderer, c.cx, c.cy, c.rad, *c.GetColour())
+	} else {
+		gfx.CircleColor(renderer, c.cx, c.cy, c.rad, *c.GetColour())
+	}
+}
+
+func (c *SDL_CanvasCircle) Inside(x, y int32) bool {
+	dx := int64(x - c.cx)
+	dy := int64(y - c.cy)
+	r := int64(c.rad)
+	return (dx*dx)+(dy*dy) <= r*r
+}
+
+func (c *SDL_CanvasCircle) Move(dx, dy int32) {
+	c.cx, c.cy = c.cx+dx, c.cy+dy
+}
+
+func (c *SDL_CanvasCircle) Scale(s float32) {
+	c.cx, c.cy, c.rad = scaleInt32(c.cx, s), scaleInt32(c.cy, s), scaleInt32(c.rad, s)
+}
+
+/****************************************************************************************
+* SDL_CanvasPolygon code
+**/
+type SDL_CanvasPolygon struct {
+	sdl_CanvasPrimitiveBase
+	vx, vy []int16
+}
+
+/*
+Set the vertices. vx and vy must be the same length. Extra values in the longer list are ignored.
+*/
+func (p *SDL_CanvasPolygon) SetPoints(vx, vy []int32) {
+	n := len(vx)
+	if len(vy) < n {
+		n = len(vy)
+	}
+	p.vx = make([]int16, n)
+	p.vy = make([]int16, n)
+	for i := 0; i < n; i++ {
+		p.vx[i] = int16(vx[i])
+		p.vy[i] = int16(vy[i])
+	}
+}
+
+func (p *SDL_CanvasPolygon) Draw(renderer *sdl.Renderer, font *ttf.Font) {
+	if len(p.vx) < 3 {
+		return
+	}
+	if p.filled {
+		gfx.FilledPolygonColor(renderer, p.vx, p.vy, *p.GetColour())
+	} else {
+		gfx.PolygonColor(renderer, p.vx, p.vy, *p.GetColour())
+	}
+}
+
+/*
+Even-odd rule. Cast a ray to the right and count the edges crossed.
+*/
+func (p *SDL_CanvasPolygon) Inside(x, y int32) bool {
+	n := len(p.vx)
+	if n < 3 {
+		return false
+	}
+	px := float64(x)
+	py := float64(y)
+	in := false
+	j := n - 1
+	for i := 0; i < n; i++ {
+		xi, yi := float64(p.vx[i]), float64(p.vy[i])
+		xj, yj := float64(p.vx[j]), float64(p.vy[j])
+		if (yi > py) != (yj > py) && px < (xj-xi)*(py-yi)/(yj-yi)+xi {
+			in = !in
+		}
+		j = i
+	}
+	return in
+}
+
+func (p *SDL_CanvasPolygon) Move(dx, dy int32) {
+	for i := 0; i < len(p.vx); i++ {
+		p.vx[i] = p.vx[i] + int16(dx)
+		p.vy[i] = p.vy[i] + int16(dy)
+	}
+}
+
+func (p *SDL_CanvasPolygon) Scale(s float32) {
+	for i := 0; i < len(p.vx); i++ {
+		p.vx[i] = int16(float32(p.vx[i]) * s)
+		p.vy[i] = int16(float32(p.vy[i]) * s)
+	}
+}
+
+/****************************************************************************************
+* SDL_CanvasText code
+* The width of the text is only known after it has been drawn.
+**/
+type SDL_CanvasText struct {
+	sdl_CanvasPrimitiveBase
+	x, y, w, h int32
+	text       string
+	cacheKey   string
+}
+
+func (t *SDL_CanvasText) SetText(text string) {
+	t.text = text
+}
+
+func (t *SDL_CanvasText) GetText() string {
+	return t.text
+}
+
+func (t *SDL_CanvasText) SetPosition(x, y int32) {
+	t.x, t.y = x, y
+}
+
+func (t *SDL_CanvasText) Draw(renderer *sdl.Renderer, font *ttf.Font) {
+	if t.text == "" || font == nil {
+		t.w = 0
+		return
+	}
+	ctwe, err := GetResourceInstance().UpdateTextureFromString(renderer, t.cacheKey, t.text, font, t.GetColour())
+	if err != nil || ctwe.h == 0 {
+		t.w = 0
+		return
+	}
+	t.w = int32(float32(ctwe.w) * (float32(t.h) / float32(ctwe.h)))
+	renderer.Copy(ctwe.texture, nil, &sdl.Rect{X: t.x, Y: t.y, W: t.w, H: t.h})
+}
+
+func (t *SDL_CanvasText) Inside(x, y int32) bool {
+	return isInsideRect(x, y, &sdl.Rect{X: t.x, Y: t.y, W: t.w, H: t.h})
+}
+
+func (t *SDL_CanvasText) Move(dx, dy int32) {
+	t.x, t.y = t.x+dx, t.y+dy
+}
+
+func (t *SDL_CanvasText) Scale(s float32) {
+	t.x, t.y, t.w, t.h = scaleInt32(t.x, s), scaleInt32(t.y, s), scaleInt32(t.w, s), scaleInt32(t.h, s)
+}
+
+/****************************************************************************************
+* SDL_CanvasImage code
+* Draws a texture from the resource texture cache. See AddTexturesFromFileMap.
+**/
+type SDL_CanvasImage struct {
+	sdl_CanvasPrimitiveBase
+	rect        sdl.Rect
+	textureName string
+}
+
+func (im *SDL_CanvasImage) SetTextureName(name string) {
+	im.textureName = name
+}
+
+func (im *SDL_CanvasImage) SetRect(rect *sdl.Rect) {
+	im.rect = *rect
+}
+
+func (im *SDL_CanvasImage) Draw(renderer *sdl.Renderer, font *ttf.Font) {
+	image, iw, ih, err := GetResourceInstance().GetTextureForName(im.textureName)
+	if err != nil {
+		renderer.SetDrawColor(255, 0, 0, 255)
+		renderer.DrawRect(&sdl.Rect{X: im.rect.X, Y: im.rect.Y, W: ifZeroUseN(im.rect.W, 20), H: ifZeroUseN(im.rect.H, 20)})
+		return
+	}
+	if im.rect.W <= 0 {
+		im.rect.W = iw
+	}
+	if im.rect.H <= 0 {
+		im.rect.H = ih
+	}
+	renderer.Copy(image, nil, &im.rect)
+}
+
+func (im *SDL_CanvasImage) Inside(x, y int32) bool {
+	return isInsideRect(x, y, &im.rect)
+}
+
+func (im *SDL_CanvasImage) Move(dx, dy int32) {
+	im.rect.X = im.rect.X + dx
+	im.rect.Y = im.rect.Y + dy
+}
+
+func (im *SDL_CanvasImage) Scale(s float32) {
+	im.rect = sdl.Rect{X: scaleInt32(im.rect.X, s), Y: scaleInt32(im.rect.Y, s), W: scaleInt32(im.rect.W, s), H: scaleInt32(im.rect.H, s)}
+}
+
+/****************************************************************************************
+* SDL_Canvas code
+* Implements SDL_Widget cos it is one!
+* Holds a list of retained primitives drawn in the order they were added.
+* The onDraw hook is called after the primitives are drawn. The renderer viewport is set to the
+* canvas so 0,0 is the top left of the canvas and drawing is clipped to the canvas.
+**/
+type SDL_Canvas struct {
+	SDL_WidgetBase
+	primitives       []SDL_CanvasPrimitive
+	primitiveLock    sync.Mutex
+	onDraw           func(*sdl.Renderer, *ttf.Font, *sdl.Rect)
+	onPrimitiveClick func(SDL_CanvasPrimitive, int32, int32) bool
+	onZoom           func(float32, int32, int32) bool
+	primitivePressed bool // The mouse went down on a primitive. onPrimitiveClick is called on release
+}
+
+var _ SDL_Widget = (*SDL_Canvas)(nil)   // Ensure SDL_Canvas 'is a' SDL_Widget
+var _ SDL_Zoomable = (*SDL_Canvas)(nil) // Ensure SDL_Canvas 'is a' SDL_Zoomable
+
+func NewSDLCanvas(x, y, w, h, id int32, style STATE_BITS, onDraw func(*sdl.Renderer, *ttf.Font, *sdl.Rect)) *SDL_Canvas {
+	c := &SDL_Canvas{primitives: make([]SDL_CanvasPrimitive, 0), onDraw: onDraw}
+	c.SDL_WidgetBase = initBase(x, y, w, h, id, c, 0, false, style, nil)
+	return c
+}
+
+func (c *SDL_Canvas) SetOnDraw(f func(*sdl.Renderer, *ttf.Font, *sdl.Rect)) {
+	c.onDraw = f
+}
+
+/*
+Called when a primitive is clicked with the left button. x and y are local to the canvas.
+If there is no primitive under the mouse the onClick function is called.
+*/
+func (c *SDL_Canvas) SetOnPrimitiveClick(f func(SDL_CanvasPrimitive, int32, int32) bool) {
+	c.onPrimitiveClick = f
+}
+
+/*
+Add a primitive. Any primitive with the same id is replaced.
+*/
+func (c *SDL_Canvas) Add(p SDL_CanvasPrimitive) SDL_CanvasPrimitive {
+	c.primitiveLock.Lock()
+	defer c.primitiveLock.Unlock()
+	for i, pp := range c.primitives {
+		if pp.GetId() == p.GetId() {
+			c.primitives[i] = p
+			return p
+		}
+	}
+	c.primitives = append(c.primitives, p)
+	return p
+}
+
+func (c *SDL_Canvas) AddLine(id, x1, y1, x2, y2 int32, colour *sdl.Color) *SDL_CanvasLine {
+	l := &SDL_CanvasLine{sdl_CanvasPrimitiveBase: newCanvasPrimitiveBase(id, colour, false), x1: x1, y1: y1, x2: x2, y2: y2, width: 1}
+	c.Add(l)
+	return l
+}
+
+func (c *SDL_Canvas) AddRect(id, x, y, w, h int32, colour *sdl.Color, filled bool) *SDL_CanvasRect {
+	r := &SDL_CanvasRect{sdl_CanvasPrimitiveBase: newCanvasPrimitiveBase(id, colour, filled), rect: sdl.Rect{X: x, Y: y, W: w, H: h}}
+	c.Add(r)
+	return r
+}
+
+func (c *SDL_Canvas) AddCircle(id, cx, cy, rad int32, colour *sdl.Color, filled bool) *SDL_CanvasCircle {
+	ci := &SDL_CanvasCircle{sdl_CanvasPrimitiveBase: newCanvasPrimitiveBase(id, colour, filled), cx: cx, cy: cy, rad: rad}
+	c.Add(ci)
+	return ci
+}
+
+func (c *SDL_Canvas) AddPolygon(id int32, vx, vy []int32, colour *sdl.Color, filled bool) *SDL_CanvasPolygon {
+	p := &SDL_CanvasPolygon{sdl_CanvasPrimitiveBase: newCanvasPrimitiveBase(id, colour, filled)}
+	p.SetPoints(vx, vy)
+	c.Add(p)
+	return p
+}
+
+func (c *SDL_Canvas) AddText(id, x, y, h int32, text string, colour *sdl.Color) *SDL_CanvasText {
+	t := &SDL_CanvasText{sdl_CanvasPrimitiveBase: newCanvasPrimitiveBase(id, colour, false), x: x, y: y, h: h, text: text, cacheKey: fmt.Sprintf("canvas:%d:%d:%d", c.widgetId, id, rand.Intn(100))}
+	c.Add(t)
+	return t
+}
+
+func (c *SDL_Canvas) AddImage(id, x, y, w, h int32, textureName string) *SDL_CanvasImage {
+	im := &SDL_CanvasImage{sdl_CanvasPrimitiveBase: newCanvasPrimitiveBase(id, nil, false), rect: sdl.Rect{X: x, Y: y, W: w, H: h}, textureName: textureName}
+	c.Add(im)
+	return im
+}
+
+func (c *SDL_Canvas) GetPrimitive(id int32) SDL_CanvasPrimitive {
+	c.primitiveLock.Lock()
+	defer c.primitiveLock.Unlock()
+	for _, p := range c.primitives {
+		if p.GetId() == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (c *SDL_Canvas) ListPrimitives() []SDL_CanvasPrimitive {
+	c.primitiveLock.Lock()
+	defer c.primitiveLock.Unlock()
+	l := make([]SDL_CanvasPrimitive, len(c.primitives))
+	copy(l, c.primitives)
+	return l
+}
+
+func (c *SDL_Canvas) Remove(id int32) bool {
+	c.primitiveLock.Lock()
+	defer c.primitiveLock.Unlock()
+	for i, p := range c.primitives {
+		if p.GetId() == id {
+			c.primitives = append(c.primitives[:i], c.primitives[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *SDL_Canvas) Clear() {
+	c.primitiveLock.Lock()
+	defer c.primitiveLock.Unlock()
+	c.primitives = make([]SDL_CanvasPrimitive, 0)
+}
+
+/*
+Return the top most visible primitive at screen position x,y. nil if there are none.
+*/
+func (c *SDL_Canvas) PrimitiveAt(x, y int32) SDL_CanvasPrimitive {
+	c.primitiveLock.Lock()
+	defer c.primitiveLock.Unlock()
+	lx, ly := c.ToLocal(x, y)
+	for i := len(c.primitives) - 1; i >= 0; i-- {
+		p := c.primitives[i]
+		if p.IsVisible() && p.Inside(lx, ly) {
+			return p
+		}
+	}
+	return nil
+}
+
+/*
+Convert a screen position to a position local to the canvas
+*/
+func (c *SDL_Canvas) ToLocal(x, y int32) (int32, int32) {
+	return x - c.x, y - c.y
+}
+
+/*
+A left click on a primitive calls onPrimitiveClick when the button is released over the canvas.
+Right and middle clicks (and a long press) go to the click listeners.
+*/
+func (c *SDL_Canvas) Click(md *SDL_MouseData) bool {
+	if c.primitivePressed {
+		return true
+	}
+	if c.IsEnabled() && !md.IsDragging() {
+		if c.primitiveAt(md) != nil {
+			if md.IsDown() {
+				c.primitivePressed = true
+				return true
+			}
+			return c.firePrimitiveClick(md)
+		}
+		return c.SDL_WidgetBase.Click(md)
+	}
+	return false
+}
+
+func (c *SDL_Canvas) Release(md *SDL_MouseData) bool {
+	if !c.primitivePressed {
+		return c.SDL_WidgetBase.Release(md)
+	}
+	c.primitivePressed = false
+	if !c.IsVisible() || !isInsideRect(md.GetX(), md.GetY(), c.GetRect()) || c.primitiveAt(md) == nil {
+		return false
+	}
+	return c.firePrimitiveClick(md)
+}
+
+/*
+The primitive under the mouse if it is a left click and onPrimitiveClick is set
+*/
+func (c *SDL_Canvas) primitiveAt(md *SDL_MouseData) SDL_CanvasPrimitive {
+	if c.onPrimitiveClick == nil {
+		return nil
+	}
+	kind := clickKind(md)
+	if kind != CLICK_KIND_CLICK && kind != CLICK_KIND_DOUBLE_CLICK {
+		return nil
+	}
+	return c.PrimitiveAt(md.GetX(), md.GetY())
+}
+
+func (c *SDL_Canvas) firePrimitiveClick(md *SDL_MouseData) bool {
+	p := c.PrimitiveAt(md.GetX(), md.GetY())
+	if p == nil {
+		return false
+	}
+	lx, ly := c.ToLocal(md.GetX(), md.GetY())
+	return c.onPrimitiveClick(p, lx, ly)
+}
+
+/*
+Called by Zoom (a touch pinch) with the factor and the local position of the pinch centre.
+If it is not set Zoom scales the primitives about the pinch centre.
+*/
+func (c *SDL_Canvas) SetOnZoom(f func(float32, int32, int32) bool) {
+	c.onZoom = f
+}
+
+func (c *SDL_Canvas) Zoom(factor float32, x, y int32) bool {
+	if !c.IsEnabled() || factor <= 0 {
+		return false
+	}
+	lx, ly := c.ToLocal(x, y)
+	if c.onZoom != nil {
+		return c.onZoom(factor, lx, ly)
+	}
+	c.primitiveLock.Lock()
+	defer c.primitiveLock.Unlock()
+	dx, dy := lx-scaleInt32(lx, factor), ly-scaleInt32(ly, factor)
+	for _, p := range c.primitives {
+		p.Scale(factor)
+		p.Move(dx, dy)
+	}
+	return true
+}
+
+func (c *SDL_Canvas) Scale(s float32) {
+	c.SDL_WidgetBase.Scale(s)
+	c.primitiveLock.Lock()
+	defer c.primitiveLock.Unlock()
+	for _, p := range c.primitives {
+		p.Scale(s)
+	}
+}
+
+func (c *SDL_Canvas) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
+	if c.IsVisible() {
+		if c.ShouldDrawBackground() {
+			bc := c.GetBackground()
+			renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
+			renderer.FillRect(&sdl.Rect{X: c.x, Y: c.y, W: c.w, H: c.h})
+		}
+		viewPort := renderer.GetViewport()
+		clipRect := renderer.GetClipRect()
+		local := &sdl.Rect{X: 0, Y: 0, W: c.w, H: c.h}
+		renderer.SetViewport(&sdl.Rect{X: viewPort.X + c.x, Y: viewPort.Y + c.y, W: c.w, H: c.h})
+		renderer.SetClipRect(local)
+		c.primitiveLock.Lock()
+		for _, p := range c.primitives {
+			if p.IsVisible() {
+				p.Draw(renderer, font)
+			}
+		}
+		c.primitiveLock.Unlock()
+		if c.onDraw != nil {
+			c.onDraw(renderer, font, local)
+		}
+		renderer.SetViewport(&viewPort)
+		if clipRect.W > 0 && clipRect.H > 0 {
+			renderer.SetClipRect(&clipRect)
+		} else {
+			renderer.SetClipRect(nil)
+		}
+		if c.ShouldDrawBorder() {
+			bc := c.GetBorderColour()
+			renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
+			renderer.DrawRect(&sdl.Rect{X: c.x + 1, Y: c.y + 1, W: c.w - 2, H: c.h - 2})
+		}
+	}
+	return nil
+}
+
+func newCanvasPrimitiveBase(id int32, colour *sdl.Color, filled bool) sdl_CanvasPrimitiveBase {
+	return sdl_CanvasPrimitiveBase{id: id, colour: colour, filled: filled, visible: true}
+}
+
+/*
+Square of the distance from point px,py to the line segment x1,y1 x2,y2
+*/
+func canvasDistToSegmentSq(px, py, x1, y1, x2, y2 float64) float64 {
+	dx := x2 - x1
+	dy := y2 - y1
+	lenSq := (dx * dx) + (dy * dy)
+	t := 0.0
+	if lenSq > 0 {
+		t = (((px - x1) * dx) + ((py - y1) * dy)) / lenSq
+		if t < 0 {
+			t = 0
+		}
+		if t > 1 {
+			t = 1
+		}
+	}
+	cx := x1 + (t * dx)
+	cy := y1 + (t * dy)
+	return ((px - cx) * (px - cx)) + ((py - cy) * (py - cy))
+}
+
+func scaleInt32(i int32, s float32) int32 {
+	return int32(float32(i) * s)
+}
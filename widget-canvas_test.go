@@ -0,0 +1,78 @@
+package go_sdl_widget
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestCanvasHitTest(t *testing.T) {
+	c := NewSDLCanvas(100, 100, 200, 200, 1, WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	c.AddRect(1, 10, 10, 50, 50, nil, true)
+	c.AddCircle(2, 40, 40, 10, nil, true)
+	c.AddLine(3, 100, 100, 150, 150, nil)
+	c.AddPolygon(4, []int32{100, 150, 100}, []int32{10, 10, 60}, nil, true)
+
+	assertPrimitiveAt(t, "Rect", c, 115, 115, 1)
+	assertPrimitiveAt(t, "Circle on top of rect", c, 140, 140, 2)
+	assertPrimitiveAt(t, "Line", c, 226, 224, 3)
+	assertPrimitiveAt(t, "Polygon", c, 210, 120, 4)
+	assertPrimitiveAt(t, "Outside polygon", c, 245, 155, 0)
+	assertPrimitiveAt(t, "Nothing", c, 290, 290, 0)
+
+	c.GetPrimitive(2).SetVisible(false)
+	assertPrimitiveAt(t, "Hidden circle", c, 140, 140, 1)
+
+	c.AddRect(2, 0, 0, 5, 5, nil, true)
+	assertInt(t, "Replaced by id", len(c.ListPrimitives()), 4)
+	c.Remove(1)
+	assertPrimitiveAt(t, "Removed rect", c, 115, 115, 0)
+}
+
+func assertPrimitiveAt(t *testing.T, message string, c *SDL_Canvas, x, y, expected int32) {
+	p := c.PrimitiveAt(x, y)
+	var id int32 = 0
+	if p != nil {
+		id = p.GetId()
+	}
+	if id != expected {
+		t.Errorf("%s: Actual %d Expected %d", message, id, expected)
+	}
+}
+
+func TestCanvasPrimitiveClick(t *testing.T) {
+	clicked := ""
+	c := NewSDLCanvas(100, 100, 200, 200, 1, WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	c.AddRect(1, 10, 10, 50, 50, nil, true)
+	c.SetOnPrimitiveClick(func(p SDL_CanvasPrimitive, x, y int32) bool {
+		clicked = clicked + "P"
+		return true
+	})
+	c.AddClickListener(CLICK_KIND_RIGHT, func(ev *SDL_ClickEvent) bool {
+		clicked = clicked + "R"
+		return true
+	})
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 1000, 1000, 1, WIDGET_STYLE_DRAW_NONE)
+	sg.Add(c)
+
+	wg.HandleEvent(mouseDown(120, 120))
+	assertString(t, "Not on mouse down", clicked, "")
+	wg.HandleEvent(mouseUp(120, 120))
+	assertString(t, "On release", clicked, "P")
+
+	wg.HandleEvent(mouseDown(120, 120))
+	wg.HandleEvent(mouseUp(500, 500))
+	assertString(t, "Released off the canvas", clicked, "P")
+
+	down := mouseDown(120, 120)
+	down.Button = sdl.BUTTON_RIGHT
+	up := mouseUp(120, 120)
+	up.Button = sdl.BUTTON_RIGHT
+	wg.HandleEvent(down)
+	wg.HandleEvent(up)
+	assertString(t, "Right click", clicked, "PR")
+
+	c.Click(&SDL_MouseData{x: 120, y: 120, button: sdl.BUTTON_RIGHT, clickCount: 1})
+	assertString(t, "Long press", clicked, "PRR")
+}
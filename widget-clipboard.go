@@ -0,0 +1,152 @@
+package go_sdl_widget
+
+import (
+	"sync"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const CLIPBOARD_DEFAULT_HISTORY = 10 // Entries kept by a history clipboard if max is 0
+
+/*
+The clipboard used by the widgets. Set it with GetResourceInstance().SetClipboard.
+The default uses the SDL (system) clipboard.
+*/
+type SDL_Clipboard interface {
+	SetText(string) error
+	GetText() (string, error)
+}
+
+/*
+A clipboard that keeps the text copied to it. Newest first.
+SDL_Entry shows the history in a popup on Ctrl+Shift+V.
+*/
+type SDL_ClipboardHistory interface {
+	SDL_Clipboard
+	GetHistory() []string
+	ClearHistory()
+}
+
+/****************************************************************************************
+* sdl_SystemClipboard code
+* The default. Uses the SDL clipboard.
+**/
+type sdl_SystemClipboard struct{}
+
+var _ SDL_Clipboard = (*sdl_SystemClipboard)(nil) // Ensure sdl_SystemClipboard 'is a' SDL_Clipboard
+
+func (c *sdl_SystemClipboard) SetText(text string) error {
+	return sdl.SetClipboardText(text)
+}
+
+func (c *sdl_SystemClipboard) GetText() (string, error) {
+	return sdl.GetClipboardText()
+}
+
+/****************************************************************************************
+* SDL_MemoryClipboard code
+* Keeps the text in memory. For tests or to keep the clipboard local to the app.
+**/
+type SDL_MemoryClipboard struct {
+	text string
+	lock sync.Mutex
+}
+
+var _ SDL_Clipboard = (*SDL_MemoryClipboard)(nil) // Ensure SDL_MemoryClipboard 'is a' SDL_Clipboard
+
+func NewMemoryClipboard() *SDL_MemoryClipboard {
+	return &SDL_MemoryClipboard{}
+}
+
+func (c *SDL_MemoryClipboard) SetText(text string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.text = text
+	return nil
+}
+
+func (c *SDL_MemoryClipboard) GetText() (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.text, nil
+}
+
+/****************************************************************************************
+* SDL_HistoryClipboard code
+* Wraps another clipboard and keeps the last max texts set. Copying a text that is
+* already in the history moves it to the front.
+**/
+type SDL_HistoryClipboard struct {
+	clipboard SDL_Clipboard
+	history   []string
+	max       int
+	lock      sync.Mutex
+}
+
+var _ SDL_ClipboardHistory = (*SDL_HistoryClipboard)(nil) // Ensure SDL_HistoryClipboard 'is a' SDL_ClipboardHistory
+
+/*
+If clipboard is nil the SDL clipboard is used. If max is 0 CLIPBOARD_DEFAULT_HISTORY is used.
+*/
+func NewHistoryClipboard(clipboard SDL_Clipboard, max int) *SDL_HistoryClipboard {
+	if clipboard == nil {
+		clipboard = &sdl_SystemClipboard{}
+	}
+	if max <= 0 {
+		max = CLIPBOARD_DEFAULT_HISTORY
+	}
+	return &SDL_HistoryClipboard{clipboard: clipboard, max: max, history: make([]string, 0)}
+}
+
+func (c *SDL_HistoryClipboard) SetText(text string) error {
+	err := c.clipboard.SetText(text)
+	if err != nil {
+		return err
+	}
+	c.add(text)
+	return nil
+}
+
+/*
+Text put on the wrapped clipboard by another app is added to the history when it is read
+*/
+func (c *SDL_HistoryClipboard) GetText() (string, error) {
+	text, err := c.clipboard.GetText()
+	if err != nil {
+		return "", err
+	}
+	c.add(text)
+	return text, nil
+}
+
+func (c *SDL_HistoryClipboard) add(text string) {
+	if text == "" {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i, h := range c.history {
+		if h == text {
+			c.history = append(c.history[:i], c.history[i+1:]...)
+			break
+		}
+	}
+	c.history = append([]string{text}, c.history...)
+	if len(c.history) > c.max {
+		c.history = c.history[:c.max]
+	}
+}
+
+func (c *SDL_HistoryClipboard) GetHistory() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	l := make([]string, len(c.history))
+	copy(l, c.history)
+	return l
+}
+
+func (c *SDL_HistoryClipboard) ClearHistory() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.history = make([]string, 0)
+}
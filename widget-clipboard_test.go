@@ -0,0 +1,89 @@
+package go_sdl_widget
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Use a memory clipboard for the rest of the test. The SDL clipboard needs SDL video to be initialised
+*/
+func useMemoryClipboard(t *testing.T) *SDL_MemoryClipboard {
+	mc := NewMemoryClipboard()
+	GetResourceInstance().SetClipboard(mc)
+	t.Cleanup(func() { GetResourceInstance().SetClipboard(nil) })
+	return mc
+}
+
+func TestMemoryClipboard(t *testing.T) {
+	res := GetResourceInstance()
+	mc := useMemoryClipboard(t)
+
+	e := NewSDLEntry(0, 0, 200, 20, 1, "hello", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	e.SetSelectedTextBounds(0, 1)
+	ctrlKey(e, sdl.K_c)
+	s, _ := mc.GetText()
+	assertString(t, "Copied to memory", s, "he")
+
+	mc.SetText("XY")
+	e.SetCursor(99)
+	e.ClearSelection()
+	ctrlKey(e, sdl.K_v)
+	assertString(t, "Pasted from memory", e.GetText(), "helloXY")
+
+	res.SetClipboard(nil)
+	_, ok := res.GetClipboard().(*sdl_SystemClipboard)
+	assertBool(t, "Default", "GetClipboard", ok, true)
+}
+
+func TestHistoryClipboard(t *testing.T) {
+	hc := NewHistoryClipboard(NewMemoryClipboard(), 3)
+	hc.SetText("a")
+	hc.SetText("b")
+	hc.SetText("c")
+	hc.SetText("a")
+	assertString(t, "Moved to front", strings.Join(hc.GetHistory(), ","), "a,c,b")
+	hc.SetText("d")
+	assertString(t, "Max", strings.Join(hc.GetHistory(), ","), "d,a,c")
+	s, _ := hc.GetText()
+	assertString(t, "GetText", s, "d")
+	hc.ClearHistory()
+	assertInt(t, "Cleared", len(hc.GetHistory()), 0)
+
+	res := GetResourceInstance()
+	defer res.SetClipboard(nil)
+	res.SetClipboard(hc)
+	e := NewSDLEntry(0, 0, 200, 20, 1, "one two", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	assertBool(t, "Empty history", "ShowPasteHistory", e.ShowPasteHistory(), false)
+	e.SetSelectedTextBounds(0, 2)
+	ctrlKey(e, sdl.K_c)
+	e.SetSelectedTextBounds(4, 6)
+	ctrlKey(e, sdl.K_c)
+
+	e.KeyPress(sdl.K_END, true, true)
+	e.KeyPress(sdl.K_LSHIFT, true, true)
+	ctrlKey(e, sdl.K_v)
+	e.KeyPress(sdl.K_LSHIFT, true, false)
+	assertBool(t, "Ctrl+Shift+V", "IsPasteHistoryOpen", e.IsPasteHistoryOpen(), true)
+	e.KeyPress(sdl.K_DOWN, true, true)
+	e.KeyPress(sdl.K_RETURN, true, true)
+	assertString(t, "Paste from history", e.GetText(), "one twoone")
+	assertBool(t, "Closed", "IsPasteHistoryOpen", e.IsPasteHistoryOpen(), false)
+
+	e.ShowPasteHistory()
+	assertBool(t, "Inside popup", "insidePopup", e.insidePopup(5, 25), true)
+	md := &SDL_MouseData{x: 5, y: 25}
+	e.Click(md)
+	assertString(t, "Click from history", e.GetText(), "one twoonetwo")
+
+	e.ShowPasteHistory()
+	e.KeyPress(sdl.K_ESCAPE, true, true)
+	assertBool(t, "Escape", "IsPasteHistoryOpen", e.IsPasteHistoryOpen(), false)
+
+	res.SetClipboard(NewMemoryClipboard())
+	assertBool(t, "No history", "ShowPasteHistory", e.ShowPasteHistory(), false)
+}
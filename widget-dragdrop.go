@@ -0,0 +1,284 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+type DRAG_PAYLOAD_TYPE int
+
+const (
+	DRAG_PAYLOAD_TEXT   DRAG_PAYLOAD_TYPE = iota // Plain text
+	DRAG_PAYLOAD_FILE                            // A file or directory path
+	DRAG_PAYLOAD_WIDGET                          // A widget id. The widget is GetWidget()
+
+	dragDrop_THRESHOLD   int32 = 4  // Pixels the mouse must move with the button down before a drag starts
+	dragDrop_IMAGE_OFFS  int32 = 12 // The drag image is drawn below and right of the pointer
+	dragDrop_LABEL_RUNES int   = 40 // Longer payload labels are cut short in the drag image
+
+	dragDrop_IMAGE_KEY = "dragImage" // One texture in the cache for the drag image. Updated when the label changes
+)
+
+/*
+Widgets that can start a drag. Implemented by SDL_WidgetBase using the function given to SetDragSource.
+*/
+type SDL_DragSource interface {
+	GetDragPayload(x, y int32) *SDL_DragPayload
+}
+
+/*
+Widgets that can receive a drop. Implemented by SDL_WidgetBase using the functions given to SetDropTarget.
+SDL_Entry accepts text and file paths by default.
+*/
+type SDL_DropTarget interface {
+	AcceptsDrop(*SDL_DragPayload) bool
+	Drop(p *SDL_DragPayload, x, y int32) bool
+}
+
+/****************************************************************************************
+* SDL_DragPayload code
+* What is dragged. The label is shown in the drag image.
+**/
+type SDL_DragPayload struct {
+	kind     DRAG_PAYLOAD_TYPE
+	text     string
+	widget   SDL_Widget
+	label    string
+	source   SDL_Widget
+	widgetId int32
+}
+
+func NewTextPayload(text string) *SDL_DragPayload {
+	return &SDL_DragPayload{kind: DRAG_PAYLOAD_TEXT, text: text, label: text}
+}
+
+func NewFilePayload(path string) *SDL_DragPayload {
+	return &SDL_DragPayload{kind: DRAG_PAYLOAD_FILE, text: path, label: filepath.Base(path)}
+}
+
+func NewWidgetPayload(w SDL_Widget) *SDL_DragPayload {
+	return &SDL_DragPayload{kind: DRAG_PAYLOAD_WIDGET, widget: w, widgetId: w.GetWidgetId(), text: fmt.Sprintf("%d", w.GetWidgetId()), label: fmt.Sprintf("Widget %d", w.GetWidgetId())}
+}
+
+func (p *SDL_DragPayload) String() string {
+	return fmt.Sprintf("kind:%d text:%s label:%s", p.kind, p.text, p.label)
+}
+
+func (p *SDL_DragPayload) GetKind() DRAG_PAYLOAD_TYPE {
+	return p.kind
+}
+
+/*
+The text, the file path or the widget id as a string
+*/
+func (p *SDL_DragPayload) GetText() string {
+	return p.text
+}
+
+func (p *SDL_DragPayload) GetWidget() SDL_Widget {
+	return p.widget
+}
+
+func (p *SDL_DragPayload) GetWidgetId() int32 {
+	return p.widgetId
+}
+
+/*
+The widget the drag started on. Set by SDL_WidgetGroup when the drag starts
+*/
+func (p *SDL_DragPayload) GetSource() SDL_Widget {
+	return p.source
+}
+
+func (p *SDL_DragPayload) GetLabel() string {
+	return p.label
+}
+
+func (p *SDL_DragPayload) SetLabel(label string) *SDL_DragPayload {
+	p.label = label
+	return p
+}
+
+/*
+Accept payloads of any of the kinds. For use with SetDropTarget
+*/
+func AcceptPayloadKinds(kinds ...DRAG_PAYLOAD_TYPE) func(*SDL_DragPayload) bool {
+	return func(p *SDL_DragPayload) bool {
+		for _, k := range kinds {
+			if p.kind == k {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+/*
+The drag in progress in an SDL_WidgetGroup
+*/
+type sdl_Drag struct {
+	payload *SDL_DragPayload
+	source  SDL_Widget
+	target  SDL_Widget // The widget under the pointer that accepts the payload. nil if none
+}
+
+/*
+Called with the position the mouse went down. Return nil if there is nothing to drag from there.
+*/
+func (b *SDL_WidgetBase) SetDragSource(f func(x, y int32) *SDL_DragPayload) {
+	b.dragSource = f
+}
+
+func (b *SDL_WidgetBase) GetDragPayload(x, y int32) *SDL_DragPayload {
+	if b.dragSource == nil || !b.IsEnabled() {
+		return nil
+	}
+	return b.dragSource(x, y)
+}
+
+/*
+accept is called while a payload is dragged over the widget. If it returns true the widget is highlighted.
+If accept is nil any payload is accepted. onDrop is called when an accepted payload is dropped.
+Set onDrop to nil to stop the widget being a drop target.
+*/
+func (b *SDL_WidgetBase) SetDropTarget(accept func(*SDL_DragPayload) bool, onDrop func(*SDL_DragPayload, int32, int32) bool) {
+	b.acceptDrop = accept
+	b.onDrop = onDrop
+}
+
+func (b *SDL_WidgetBase) AcceptsDrop(p *SDL_DragPayload) bool {
+	if b.onDrop == nil || !b.IsEnabled() || !b.IsVisible() {
+		return false
+	}
+	return b.acceptDrop == nil || b.acceptDrop(p)
+}
+
+func (b *SDL_WidgetBase) Drop(p *SDL_DragPayload, x, y int32) bool {
+	if !b.AcceptsDrop(p) {
+		return false
+	}
+	return b.onDrop(p, x, y)
+}
+
+/*
+The drag in progress. nil if nothing is being dragged
+*/
+func (wg *SDL_WidgetGroup) GetDragPayload() *SDL_DragPayload {
+	if wg.drag == nil {
+		return nil
+	}
+	return wg.drag.payload
+}
+
+/*
+Called on mouse motion with the left button down. Starts a drag when the mouse has moved far enough from where
+the button went down on a drag source. The press on the source is cancelled so it is not clicked.
+*/
+func (wg *SDL_WidgetGroup) startDrag(x, y int32) bool {
+	w := wg.mouseDown
+	md := wg.mouseData
+	if w == nil || md.button != sdl.BUTTON_LEFT {
+		return false
+	}
+	if abs32(x-wg.downX) < dragDrop_THRESHOLD && abs32(y-wg.downY) < dragDrop_THRESHOLD {
+		return false
+	}
+	s, ok := w.(SDL_DragSource)
+	if !ok {
+		return false
+	}
+	p := s.GetDragPayload(wg.downX, wg.downY)
+	if p == nil {
+		return false
+	}
+	p.source = w
+	w.Release(&SDL_MouseData{x: math.MinInt32, y: math.MinInt32})
+	wg.drag = &sdl_Drag{payload: p, source: w}
+	wg.moveDrag(x, y)
+	return true
+}
+
+/*
+Move the drag target to the widget under x,y that accepts the payload. If the widget does not accept it
+the containers it is in are tried, up to the sub group. A widget can not be dropped on itself.
+*/
+func (wg *SDL_WidgetGroup) moveDrag(x, y int32) {
+	wg.drag.target = wg.bubbleAt(x, y, func(w SDL_Widget) bool {
+		t, ok := w.(SDL_DropTarget)
+		return ok && w != wg.drag.source && t.AcceptsDrop(wg.drag.payload)
+	})
+}
+
+/*
+Drop the payload on the target. The target is focused first (if it can be) so an entry can place the cursor.
+*/
+func (wg *SDL_WidgetGroup) endDrag(x, y int32) bool {
+	d := wg.drag
+	wg.drag = nil
+	wg.mouseDown = nil
+	wg.mouseData.ActionReset(&sdl.MouseButtonEvent{X: x, Y: y})
+	if d.target == nil {
+		return false
+	}
+	if d.target.CanFocus() {
+		wg.moveFocus(d.target)
+	}
+	return d.target.(SDL_DropTarget).Drop(d.payload, x, y)
+}
+
+/*
+Stop the drag without dropping. Escape does this while dragging
+*/
+func (wg *SDL_WidgetGroup) CancelDrag() {
+	if wg.drag != nil {
+		wg.drag = nil
+		wg.mouseDown = nil
+		wg.mouseData.down = false
+		wg.mouseData.ActionReset(&sdl.MouseButtonEvent{X: wg.mouseX, Y: wg.mouseY})
+	}
+}
+
+/*
+Highlight the target and draw the payload label in a box following the pointer
+*/
+func (d *sdl_Drag) draw(renderer *sdl.Renderer, font *ttf.Font, x, y int32) {
+	res := GetResourceInstance()
+	if d.target != nil {
+		c := res.GetDropTargetColour()
+		r := d.target.GetRect()
+		renderer.SetDrawColor(c.R, c.G, c.B, c.A)
+		renderer.DrawRect(&sdl.Rect{X: r.X - 2, Y: r.Y - 2, W: r.W + 4, H: r.H + 4})
+		renderer.DrawRect(&sdl.Rect{X: r.X - 1, Y: r.Y - 1, W: r.W + 2, H: r.H + 2})
+	}
+	label := d.payload.label
+	if utf8.RuneCountInString(label) > dragDrop_LABEL_RUNES {
+		label = string([]rune(label)[:dragDrop_LABEL_RUNES-3]) + "..."
+	}
+	if label == "" || font == nil {
+		return
+	}
+	ctwe, err := res.UpdateTextureFromString(renderer, dragDrop_IMAGE_KEY, label, font, res.GetColour(WIDGET_COLOUR_INDEX_ENABLED, WIDGET_COLOUR_STYLE_FG))
+	if err != nil {
+		return
+	}
+	box := &sdl.Rect{X: x + dragDrop_IMAGE_OFFS, Y: y + dragDrop_IMAGE_OFFS, W: ctwe.w + 8, H: ctwe.h + 4}
+	bg := res.GetColour(WIDGET_COLOUR_INDEX_ENABLED, WIDGET_COLOUR_STYLE_BG)
+	renderer.SetDrawColor(bg.R, bg.G, bg.B, bg.A)
+	renderer.FillRect(box)
+	renderer.Copy(ctwe.texture, nil, &sdl.Rect{X: box.X + 4, Y: box.Y + 2, W: ctwe.w, H: ctwe.h})
+	bc := res.GetColour(WIDGET_COLOUR_INDEX_ENABLED, WIDGET_COLOUR_STYLE_BORDER)
+	renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
+	renderer.DrawRect(box)
+}
+
+func abs32(i int32) int32 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,138 @@
+package go_sdl_widget
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func mouseMove(x, y int32) *sdl.MouseMotionEvent {
+	return &sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: x, Y: y, State: sdl.BUTTON_LEFT}
+}
+
+func TestDragDropFileListToEntry(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	selected := ""
+	fl, err := NewFileList(0, 0, 20, 10, dir, nil, WIDGET_STYLE_DRAW_BG, func(s string, rc FILE_LIST_RESPONSE_CODE, id int32) bool {
+		selected = s
+		return false
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fl.Reload(dir)
+	wg := NewWidgetGroup(nil)
+	wg.NewWidgetSubGroup(0, 0, 1000, 200, 1, WIDGET_STYLE_DRAW_NONE).Add(fl)
+	sg := wg.NewWidgetSubGroup(0, 300, 400, 100, 20, WIDGET_STYLE_DRAW_NONE)
+	e := sg.Add(NewSDLEntry(10, 310, 200, 24, 21, "old", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Entry)
+	layoutEntry(e)
+
+	// Rows: D:.. F:a.txt
+	wg.HandleEvent(mouseDown(10, 45))
+	wg.HandleEvent(mouseMove(12, 46))
+	assertBool(t, "Small move", "dragging", wg.GetDragPayload() != nil, false)
+	wg.HandleEvent(mouseMove(50, 320))
+	p := wg.GetDragPayload()
+	if p == nil {
+		t.Fatal("Drag did not start")
+	}
+	abs, _ := filepath.Abs(filepath.Join(dir, "a.txt"))
+	assertInt(t, "Payload kind", int(p.GetKind()), int(DRAG_PAYLOAD_FILE))
+	assertString(t, "Payload path", p.GetText(), abs)
+	assertString(t, "Payload label", p.GetLabel(), "a.txt")
+	assertBool(t, "Target", "entry", wg.drag.target == e, true)
+
+	assertBool(t, "Drop", "used", wg.HandleEvent(mouseUp(50, 320)), true)
+	assertString(t, "Drop", e.GetText(), abs)
+	assertBool(t, "Drop", "entry focused", e.IsFocused(), true)
+	assertString(t, "Row not clicked", selected, "")
+	assertBool(t, "After drop", "dragging", wg.GetDragPayload() != nil, false)
+	e.Undo()
+	assertString(t, "Undo drop", e.GetText(), "old")
+}
+
+func TestDragDropTargets(t *testing.T) {
+	clicks := 0
+	dropped := ""
+	onClick := func(s string, id, x, y int32) bool {
+		clicks++
+		return true
+	}
+	onDrop := func(name string) func(*SDL_DragPayload, int32, int32) bool {
+		return func(p *SDL_DragPayload, x, y int32) bool {
+			dropped = name + ":" + p.GetText()
+			return true
+		}
+	}
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 400, 200, 1, WIDGET_STYLE_DRAW_NONE)
+	b := sg.Add(NewSDLButton(10, 10, 50, 24, 2, "B", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick))
+	b.(*SDL_Button).SetDragSource(func(x, y int32) *SDL_DragPayload { return NewWidgetPayload(b) })
+	l := sg.Add(NewSDLLabel(100, 10, 50, 24, 3, "L", ALIGN_LEFT, WIDGET_STYLE_DRAW_NONE)).(*SDL_Label)
+	l.SetDropTarget(AcceptPayloadKinds(DRAG_PAYLOAD_WIDGET), onDrop("label"))
+	ro := sg.Add(NewSDLEntry(200, 10, 100, 24, 4, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Entry)
+	ro.SetReadOnly(true)
+	sg.SetDropTarget(nil, onDrop("group"))
+
+	drag := func(x, y int32) {
+		wg.HandleEvent(mouseDown(20, 20))
+		wg.HandleEvent(mouseMove(x, y))
+	}
+
+	drag(110, 20)
+	assertBool(t, "Over label", "target", wg.drag.target == l, true)
+	wg.HandleEvent(mouseUp(110, 20))
+	assertString(t, "Drop on label", dropped, "label:2")
+	assertInt(t, "Source not clicked", clicks, 0)
+
+	// The read only entry does not accept it so it goes to the sub group
+	drag(210, 20)
+	assertBool(t, "Over read only entry", "target", wg.drag.target == sg, true)
+	wg.HandleEvent(mouseUp(210, 20))
+	assertString(t, "Drop on read only entry", dropped, "group:2")
+
+	// Not dropped on the source
+	dropped = ""
+	drag(25, 21)
+	assertBool(t, "Over source", "target", wg.drag.target == sg, true)
+
+	// Escape cancels
+	assertBool(t, "Escape", "used", wg.HandleEvent(keyEvent(sdl.K_ESCAPE, 0, true)), true)
+	assertBool(t, "Escape", "dragging", wg.GetDragPayload() != nil, false)
+	wg.HandleEvent(mouseUp(25, 21))
+	assertString(t, "Cancelled", dropped, "")
+	assertInt(t, "Cancelled", clicks, 0)
+
+	// No drag source. The button is clicked
+	b.(*SDL_Button).SetDragSource(nil)
+	drag(110, 20)
+	wg.HandleEvent(mouseMove(20, 20))
+	wg.HandleEvent(mouseUp(20, 20))
+	assertInt(t, "Not a drag source", clicks, 1)
+}
+
+func TestDragDropTextIntoEntry(t *testing.T) {
+	e := NewSDLEntry(10, 10, 200, 24, 1, "abcd", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	layoutEntry(e)
+	e.SetFocused(true)
+	assertBool(t, "Accepts text", "accept", e.AcceptsDrop(NewTextPayload("XY")), true)
+	assertBool(t, "Drop at end", "used", e.Drop(NewTextPayload("XY"), 190, 20), true)
+	assertString(t, "Drop at end", e.GetText(), "abcdXY")
+	e.Drop(NewTextPayload("-"), 0, 20)
+	assertString(t, "Drop at start", e.GetText(), "-abcdXY")
+
+	// Where the text goes does not depend on the focus
+	u := NewSDLEntry(10, 10, 200, 24, 2, "abcdef", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	layoutEntry(u)
+	u.Drop(NewTextPayload("X"), 35, 20)
+	assertString(t, "Drop without focus", u.GetText(), "abXcdef")
+
+	e.SetDropTarget(AcceptPayloadKinds(DRAG_PAYLOAD_WIDGET), func(p *SDL_DragPayload, x, y int32) bool { return true })
+	assertBool(t, "SetDropTarget replaces the default", "accept", e.AcceptsDrop(NewTextPayload("XY")), false)
+	e.SetDropTarget(nil, nil)
+	e.SetReadOnly(true)
+	assertBool(t, "Read only", "accept", e.AcceptsDrop(NewFilePayload("/tmp/x")), false)
+}
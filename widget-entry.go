@@ -2,47 +2,100 @@ package go_sdl_widget
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/veandco/go-sdl2/gfx"
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/veandco/go-sdl2/ttf"
 )
 
+const (
+	ENTRY_DEFAULT_MASK_RUNE rune   = '•'  // Drawn in place of each char when the entry is masked
+	ENTRY_REVEAL_MS         uint64 = 5000 // How long the reveal button shows masked text
+	ENTRY_DEFAULT_UNDO_MAX  int    = 100  // Max undo steps kept. Oldest are dropped first
+	ENTRY_CURSOR_WIDTH      int32  = 5    // Width of the insert cursor
+	ENTRY_DRAG_SCROLL_MIN   int32  = 2    // Min pixels per frame to scroll when drag selecting past an edge
+)
+
+/*
+The state of an entry before (undo) or after (redo) an edit
+*/
+type sdl_EntryEdit struct {
+	text                  string
+	cursor                int
+	selectFrom, selectToo int
+}
+
 /****************************************************************************************
-* SDL_Label code
+* SDL_Entry code
 * Implements SDL_Widget cos it is one!
 * Implements SDL_TextWidget because it has text and uses the texture cache
 **/
 type SDL_Entry struct {
 	SDL_WidgetBase
-	text             string
-	textLen          int
-	history          []string
-	cursor           int
-	cursorAtEnd      bool
-	cursorTimer      int
-	selecteCharsFwd  []byte
-	selecteCharsRev  []byte
-	selectCharFrom   int
-	selectCharToo    int
-	ctrlKeyDown      bool
-	indent           int32
-	_invalid         bool
-	leadin, leadout  int
-	dragFrom, dragTo int32
-	dragging         bool
-	onChange         func(string, string, ENTRY_EVENT_TYPE) (string, error)
-	screenData       *sdl_TextureCacheEntryRune
-	screenDataLock   sync.Mutex
-	keyPressLock     sync.Mutex
+	text            string
+	runes           []rune           // text as runes. All positions (cursor, selection) are rune indexes
+	textLen         int              // Length of text in runes
+	history         []*sdl_EntryEdit // The undo stack
+	redo            []*sdl_EntryEdit
+	undoMax         int
+	typingAt        int  // Cursor position after the last typed char. Typing here merges with the last undo step
+	overwrite       bool // Typed chars replace the char at the cursor. Toggled by the Insert key
+	validators      []SDL_Validator
+	validateOn      ENTRY_VALIDATE_ON
+	validateFailed  bool // The error is from the validators. It stays until they are run again
+	errorMessage    string
+	inputMask       *sdl_InputMask
+	inputMaskPh     rune // Placeholder drawn in empty input mask slots
+	autoComplete    *sdl_AutoComplete
+	pasteHistory    *sdl_AutoComplete // Popup list of the clipboard history. Open if it has items
+	placeholderText string            // Shown dimmed when the entry is empty and not focused
+	maxLength       int               // Max length in runes. 0 for no limit
+	readOnly        bool
+	scrollStep      float64 // Added to a number in the entry for each mouse wheel step. 0 for off
+	cursor          int
+	cursorAtEnd     bool
+	cursorTimer     int
+	selecteCharsFwd []rune
+	selecteCharsRev []rune
+	selectCharFrom  int
+	selectCharToo   int
+	ctrlKeyDown     bool
+	shiftKeyDown    bool
+	selectAnchor    int // Where a shift+cursor selection started. -1 if none
+	indent          int32
+	_invalid        bool
+	scrollX         int32 // Pixels of text scrolled off the left of the entry
+	showCursor      bool  // Scroll so the cursor is visible on the next Draw
+	dragAnchor      int   // Rune position where a drag select started
+	dragX           int32 // Mouse x while drag selecting
+	dragging        bool
+	masked          bool
+	revealed        bool
+	revealButton    bool
+	revealedAt      uint64
+	maskRune        rune
+	composition     []rune   // IME text being composed. Not part of text until committed
+	compCursor      int      // Cursor position within the composition
+	imeRect         sdl.Rect // Last rect sent to sdl.SetTextInputRect
+	onChange        func(string, string, ENTRY_EVENT_TYPE) (string, error)
+	screenData      *sdl_TextureCacheEntryRune
+	screenDataLock  sync.Mutex
+	keyPressLock    sync.Mutex
 }
 
 var _ SDL_Widget = (*SDL_Entry)(nil)        // Ensure SDL_Button 'is a' SDL_Widget
 var _ SDL_CanSelectText = (*SDL_Entry)(nil) // Ensure SDL_Button 'is a' SDL_Widget
+var _ SDL_TextInput = (*SDL_Entry)(nil)     // Ensure SDL_Entry 'is a' SDL_TextInput
+var _ sdl_Popup = (*SDL_Entry)(nil)         // Ensure SDL_Entry 'is a' sdl_Popup
+var _ SDL_Scrollable = (*SDL_Entry)(nil)    // Ensure SDL_Entry 'is a' SDL_Scrollable
 
 func NewSDLEntry(x, y, w, h, id int32, text string, style STATE_BITS, onChange func(string, string, ENTRY_EVENT_TYPE) (string, error)) *SDL_Entry {
-	ent := &SDL_Entry{text: text, textLen: len(text), cursor: 0, cursorTimer: 0, leadin: 0, leadout: 0, ctrlKeyDown: false, _invalid: true, indent: 10, onChange: onChange}
+	ent := &SDL_Entry{text: text, runes: []rune(text), textLen: utf8.RuneCountInString(text), cursor: 0, cursorTimer: 0, ctrlKeyDown: false, undoMax: ENTRY_DEFAULT_UNDO_MAX, typingAt: -1, _invalid: true, indent: 10, maskRune: ENTRY_DEFAULT_MASK_RUNE, inputMaskPh: ENTRY_DEFAULT_INPUT_MASK_PLACEHOLDER, onChange: onChange}
 	ent.ClearSelection()
 	ent.SetSelecteCharsFwd(GetResourceInstance().GetSelectCharsFwd())
 	ent.SetSelecteCharsRev(GetResourceInstance().GetSelectCharsRev())
@@ -51,7 +104,7 @@ func NewSDLEntry(x, y, w, h, id int32, text string, style STATE_BITS, onChange f
 }
 
 func (b *SDL_Entry) SetSelecteCharsFwd(s string) {
-	b.selecteCharsFwd = []byte(s)
+	b.selecteCharsFwd = []rune(s)
 }
 
 func (b *SDL_Entry) GetSelecteCharsFwd() string {
@@ -59,7 +112,7 @@ func (b *SDL_Entry) GetSelecteCharsFwd() string {
 }
 
 func (b *SDL_Entry) SetSelecteCharsRev(s string) {
-	b.selecteCharsRev = []byte(s)
+	b.selecteCharsRev = []rune(s)
 }
 
 func (b *SDL_Entry) GetSelecteCharsRev() string {
@@ -67,22 +120,118 @@ func (b *SDL_Entry) GetSelecteCharsRev() string {
 }
 
 func (b *SDL_Entry) String() string {
+	if b.masked {
+		return b.maskedText()
+	}
 	return b.text
 }
 
+/*
+In masked (password) mode each char is drawn as the mask rune. GetText still returns the real text.
+Selected text cannot be read or copied and no undo history is kept.
+*/
+func (b *SDL_Entry) SetMasked(masked bool) {
+	b.screenDataLock.Lock()
+	defer b.screenDataLock.Unlock()
+	if masked {
+		b.ClearUndo()
+	}
+	b.masked = masked
+	b.revealed = false
+	b.Invalid(true)
+}
+
+func (b *SDL_Entry) IsMasked() bool {
+	return b.masked
+}
+
+func (b *SDL_Entry) SetMaskRune(r rune) {
+	b.maskRune = r
+	b.Invalid(true)
+}
+
+/*
+Show a button at the right of a masked entry. Clicking it shows the text for ENTRY_REVEAL_MS
+*/
+func (b *SDL_Entry) SetRevealButton(show bool) {
+	b.revealButton = show
+	b.Invalid(true)
+}
+
+func (b *SDL_Entry) HasRevealButton() bool {
+	return b.revealButton
+}
+
+/*
+Temporarily show the text of a masked entry. It is hidden again after ENTRY_REVEAL_MS or when focus is lost.
+*/
+func (b *SDL_Entry) Reveal(show bool) {
+	b.screenDataLock.Lock()
+	defer b.screenDataLock.Unlock()
+	b.revealNoLock(show)
+}
+
+func (b *SDL_Entry) revealNoLock(show bool) {
+	if b.masked && b.revealed != show {
+		b.revealed = show
+		b.revealedAt = sdl.GetTicks64()
+		b.Invalid(true)
+	}
+}
+
+func (b *SDL_Entry) IsRevealed() bool {
+	return b.masked && b.revealed
+}
+
+func (b *SDL_Entry) maskedText() string {
+	return strings.Repeat(string(b.maskRune), b.textLen)
+}
+
+/*
+The IME composition as it is drawn on the screen. Masked in the same way as the text
+*/
+func (b *SDL_Entry) displayComposition() string {
+	if b.masked && !b.revealed {
+		return strings.Repeat(string(b.maskRune), len(b.composition))
+	}
+	return string(b.composition)
+}
+
+/*
+The text as it is drawn on the screen
+*/
+func (b *SDL_Entry) displayText() string {
+	if b.masked && !b.revealed {
+		return b.maskedText()
+	}
+	return b.text
+}
+
+func (b *SDL_Entry) hasRevealButton() bool {
+	return b.masked && b.revealButton
+}
+
+func (b *SDL_Entry) revealButtonRect() *sdl.Rect {
+	return &sdl.Rect{X: b.x + b.w - b.h, Y: b.y, W: b.h, H: b.h}
+}
+
 func (b *SDL_Entry) SetText(text string) {
+	if b.inputMask != nil {
+		text = string(b.inputMask.conform(text))
+	}
 	if b.text != text {
 		b.screenDataLock.Lock()
 		defer b.screenDataLock.Unlock()
 		b.text = text
-		b.textLen = len(text)
+		b.runes = []rune(text)
+		b.textLen = len(b.runes)
 		b.ClearSelection()
 		b.Invalid(true)
 	}
 }
 
 func (b *SDL_Entry) findSelecteCharsFwd() uint {
-	c := []byte(b.text)
+	c := b.runes
 	cur := b.cursor
 	for i := cur; i < len(c); i++ {
 		for _, sc := range b.selecteCharsFwd {
@@ -98,7 +247,7 @@ func (b *SDL_Entry) findSelecteCharsFwd() uint {
 }
 
 func (b *SDL_Entry) findSelecteCharsRev() uint {
-	c := []byte(b.text)
+	c := b.runes
 	cur := b.cursor
 	if cur >= len(c) {
 		cur = len(c) - 1
@@ -126,9 +275,73 @@ func (b *SDL_Entry) SetFocused(focus bool) {
 	}
 	b.screenDataLock.Lock()
 	defer b.screenDataLock.Unlock()
+	// Focusing one widget un focuses all the others. Only validate if this one had the focus
+	wasFocused := b.IsFocused()
 	b.SDL_WidgetBase.SetFocused(focus)
 	b.ClearSelection()
+	if !focus {
+		// The key up for a modifier held now goes to the widget that gets the focus
+		b.ctrlKeyDown = false
+		b.shiftKeyDown = false
+		b.revealed = false
+		b.composition = nil
+		b.closePopups()
+		if wasFocused && len(b.validators) > 0 {
+			b.Validate()
+		}
+	}
 	b.Invalid(true)
+	kb := GetResourceInstance().GetVirtualKeyboard()
+	if kb != nil {
+		kb.focusChanged(b, b.IsFocused() && !b.readOnly)
+	}
+	if b.IsFocused() && !b.readOnly {
+		b.imeRect = sdl.Rect{}
+		GetResourceInstance().startTextInput(b)
+	} else {
+		GetResourceInstance().stopTextInput(b)
+	}
+}
+
+/*
+Insert committed text from an sdl.TextInputEvent at the cursor.
+*/
+func (b *SDL_Entry) TextInput(text string) bool {
+	if b.IsEnabled() && b.IsFocused() && text != "" {
+		b.keyPressLock.Lock()
+		defer b.keyPressLock.Unlock()
+		b.composition = nil
+		newValue, cursorAfter := b.typeAtCursor(text)
+		return b.applyChange(b.text, newValue, ENTRY_EVENT_INSERT, cursorAfter)
+	}
+	return false
+}
+
+/*
+Update the IME composition from an sdl.TextEditingEvent. start is the cursor position in the composition.
+The composition is drawn underlined at the cursor, with the cursor moved to start within it.
+It is not part of the text until TextInput is called.
+*/
+func (b *SDL_Entry) TextEditing(text string, start, length int32) bool {
+	if b.IsEnabled() && b.IsFocused() && !b.readOnly {
+		b.screenDataLock.Lock()
+		defer b.screenDataLock.Unlock()
+		if text == "" {
+			b.composition = nil
+		} else {
+			b.composition = []rune(text)
+		}
+		b.compCursor = int(start)
+		if b.compCursor < 0 || b.compCursor > len(b.composition) {
+			b.compCursor = len(b.composition)
+		}
+		return true
+	}
+	return false
+}
+
+func (b *SDL_Entry) GetComposition() string {
+	return string(b.composition)
 }
 
 func (b *SDL_Entry) KeyPress(c int, ctrl bool, down bool) bool {
@@ -138,149 +351,639 @@ func (b *SDL_Entry) KeyPress(c int, ctrl bool, down bool) bool {
 		oldValue := b.text
 		newValue := b.text
 		onChangeType := ENTRY_EVENT_NONE
-		insertLen := 1
-		saveHistory := true
+		cursorAfter := b.cursor
 		if ctrl {
-			// if ctrl key then just remember its state (up or down) and return
-			if c == sdl.K_LCTRL || c == sdl.K_RCTRL {
+			// if ctrl or shift key then just remember its state (up or down) and return
+			switch c {
+			case sdl.K_LCTRL, sdl.K_RCTRL:
 				b.ctrlKeyDown = down
 				return true
+			case sdl.K_LSHIFT, sdl.K_RSHIFT:
+				b.shiftKeyDown = down
+				return true
+			}
+			// While the IME is composing it owns the editing keys
+			if len(b.composition) > 0 {
+				return true
+			}
+			// If it is NOT a ctrl or shift key then we only react on a DOWN
+			if !down {
+				return false
 			}
 			// if the control key is down then it is a control sequence like CTRL-Z
 			if b.ctrlKeyDown {
-				b.ctrlKeyDown = false // Stop repeat keys - Ctrl key must be released and pressed again
 				switch c {
 				case sdl.K_z:
-					if len(b.history) > 0 {
-						newValue = (b.history)[len(b.history)-1]
-						b.history = (b.history)[0 : len(b.history)-1]
-						saveHistory = false
+					if b.shiftKeyDown {
+						return b.redoNoLock()
 					}
+					return b.undoNoLock()
+				case sdl.K_y:
+					return b.redoNoLock()
 				case sdl.K_c:
-					sdl.SetClipboardText(b.GetSelectedText())
+					if !b.masked {
+						GetResourceInstance().GetClipboard().SetText(b.GetSelectedText())
+					}
 					return true
+				case sdl.K_x:
+					if b.masked || b.readOnly || !b.hasSelection() {
+						return true
+					}
+					GetResourceInstance().GetClipboard().SetText(b.GetSelectedText())
+					from, too := b.selectionRange()
+					newValue = b.removeRange(from, too)
+					onChangeType = ENTRY_EVENT_DELETE
+					cursorAfter = from
 				case sdl.K_v:
-					s, err := sdl.GetClipboardText()
+					if b.shiftKeyDown {
+						return b.ShowPasteHistory()
+					}
+					s, err := GetResourceInstance().GetClipboard().GetText()
 					if err == nil {
-						newValue = b.insertAtCursor(s)
+						newValue, cursorAfter = b.replaceSelection(s)
 						onChangeType = ENTRY_EVENT_INSERT
-						insertLen = len(s)
 					}
+				case sdl.K_a:
+					b.SelectAll()
+					return true
+				case sdl.K_BACKSPACE:
+					// A selection is deleted instead of the word, as plain Backspace does
+					if b.hasSelection() {
+						newValue, cursorAfter = b.replaceSelection("")
+						onChangeType = ENTRY_EVENT_BS
+					} else if from := b.wordStartRev(b.cursor); from < b.cursor {
+						newValue = b.removeRange(from, b.cursor)
+						onChangeType = ENTRY_EVENT_BS
+						cursorAfter = from
+					}
+				case sdl.K_DELETE:
+					if b.hasSelection() {
+						newValue, cursorAfter = b.replaceSelection("")
+						onChangeType = ENTRY_EVENT_DELETE
+					} else if too := b.wordStartFwd(b.cursor); too > b.cursor {
+						newValue = b.removeRange(b.cursor, too)
+						onChangeType = ENTRY_EVENT_DELETE
+					}
+				default:
+					switch c | 0x40000000 {
+					case sdl.K_RIGHT:
+						b.moveCursorSelecting(b.wordStartFwd(b.cursor))
+					case sdl.K_LEFT:
+						b.moveCursorSelecting(b.wordStartRev(b.cursor))
+					case sdl.K_HOME:
+						b.moveCursorSelecting(0)
+					case sdl.K_END:
+						b.moveCursorSelecting(b.textLen)
+					default:
+						return false
+					}
+					return true
 				}
 			} else {
-				if down {
-					if c < 32 || c == 127 {
-						switch c {
-						case sdl.K_DELETE:
-							if b.cursor < b.textLen {
-								newValue = fmt.Sprintf("%s%s", oldValue[0:b.cursor], oldValue[b.cursor+1:])
-								onChangeType = ENTRY_EVENT_DELETE
-							}
-						case sdl.K_BACKSPACE:
-							if b.cursor > 0 {
-								if b.cursor < b.textLen {
-									newValue = fmt.Sprintf("%s%s", oldValue[0:b.cursor-1], oldValue[b.cursor:])
-								} else {
-									newValue = oldValue[0 : b.textLen-1]
-								}
-								onChangeType = ENTRY_EVENT_BS
-							}
-						case sdl.K_RETURN:
-							if b.onChange != nil {
-								b.onChange("", b.text, ENTRY_EVENT_FINISH)
-							}
-						default:
-							return false
+				if c < 32 || c == 127 {
+					switch c {
+					case sdl.K_DELETE:
+						if b.hasSelection() {
+							newValue, cursorAfter = b.replaceSelection("")
+							onChangeType = ENTRY_EVENT_DELETE
+						} else if i := b.nextEditPos(b.cursor); i < b.textLen {
+							newValue = b.removeRange(i, i+1)
+							onChangeType = ENTRY_EVENT_DELETE
+						}
+					case sdl.K_BACKSPACE:
+						if b.hasSelection() {
+							newValue, cursorAfter = b.replaceSelection("")
+							onChangeType = ENTRY_EVENT_BS
+						} else if i := b.prevEditPos(b.cursor); i >= 0 {
+							newValue = b.removeRange(i, i+1)
+							onChangeType = ENTRY_EVENT_BS
+							cursorAfter = i
+						}
+					case sdl.K_TAB:
+						if p := b.openPopup(); p != nil {
+							return b.acceptPopup(p)
 						}
-					} else {
+						return false
+					case sdl.K_ESCAPE:
+						if p := b.openPopup(); p != nil {
+							p.close()
+							return true
+						}
+						return false
+					case sdl.K_RETURN:
+						if p := b.openPopup(); p != nil && p.index >= 0 {
+							return b.acceptPopup(p)
+						}
+						b.Validate()
+						if b.onChange != nil {
+							b.onChange("", b.text, ENTRY_EVENT_FINISH)
+						}
+					default:
+						return false
+					}
+				} else {
+					if p := b.openPopup(); p != nil {
 						switch c | 0x40000000 {
-						case sdl.K_RIGHT:
-							b.MoveCursor(1)
 						case sdl.K_UP:
-							b.SetCursor(99)
+							p.move(-1)
+							return true
 						case sdl.K_DOWN:
-							b.SetCursor(0)
-						case sdl.K_LEFT:
-							b.MoveCursor(-1)
-						default:
-							return false
+							p.move(1)
+							return true
 						}
 					}
-				} else {
-					// If it is NOT down then we ignore an
-					return false
+					switch c | 0x40000000 {
+					case sdl.K_RIGHT:
+						b.moveCursorSelecting(b.nextEditPos(b.cursor + 1))
+					case sdl.K_UP, sdl.K_END:
+						b.moveCursorSelecting(b.prevEditPos(b.textLen) + 1)
+					case sdl.K_DOWN, sdl.K_HOME:
+						b.moveCursorSelecting(b.nextEditPos(0))
+					case sdl.K_LEFT:
+						if i := b.prevEditPos(b.cursor); i >= 0 {
+							b.moveCursorSelecting(i)
+						} else {
+							b.moveCursorSelecting(b.cursor)
+						}
+					case sdl.K_INSERT:
+						b.SetOverwrite(!b.overwrite)
+					default:
+						return false
+					}
+					return true
 				}
 			}
-			// If it is NOT a ctrl key or a control sequence then we only react on a DOWN
-
 		} else {
 			// not a control key. insert it at the cursor
-			newValue = b.insertAtCursor(fmt.Sprintf("%c", c))
+			newValue, cursorAfter = b.typeAtCursor(fmt.Sprintf("%c", c))
 			onChangeType = ENTRY_EVENT_INSERT
 		}
-		if oldValue != newValue && b.onChange != nil {
-			var err error
-			newValue, err = b.onChange(oldValue, newValue, onChangeType)
-			b.SetError(err != nil)
+		return b.applyChange(oldValue, newValue, onChangeType, cursorAfter)
+	}
+	return false
+}
+
+/*
+Pass a change through onChange, save the undo state, update the text and move the cursor to cursorAfter.
+Returns true if the text changed.
+*/
+func (b *SDL_Entry) applyChange(oldValue, newValue string, onChangeType ENTRY_EVENT_TYPE, cursorAfter int) bool {
+	if oldValue == newValue && cursorAfter != b.cursor {
+		// Nothing changed but the cursor moved. For example an input mask literal was typed
+		b.SetCursor(cursorAfter)
+		return false
+	}
+	if b.readOnly {
+		return false
+	}
+	if b.maxLength > 0 && b.inputMask == nil {
+		l := utf8.RuneCountInString(newValue)
+		if l > b.maxLength && l > b.textLen {
+			return false
 		}
-		if newValue != oldValue {
-			if saveHistory {
-				b.pushHistory(oldValue)
-			}
-			b.SetText(newValue)
-			switch onChangeType {
-			case ENTRY_EVENT_INSERT:
-				b.MoveCursor(insertLen)
-			case ENTRY_EVENT_BS:
-				b.MoveCursor(-insertLen)
-			}
-			return true
+	}
+	var err error
+	if oldValue != newValue && b.onChange != nil {
+		newValue, err = b.onChange(oldValue, newValue, onChangeType)
+		b.setChangeError(err)
+	}
+	if newValue != oldValue {
+		typed := onChangeType == ENTRY_EVENT_INSERT && cursorAfter == b.cursor+1 && !b.hasSelection()
+		if !typed || b.typingAt != b.cursor {
+			b.pushHistory(b.editState())
 		}
+		b.redo = nil
+		b.SetText(newValue)
+		b.SetCursor(cursorAfter)
+		b.typingAt = -1
+		if typed {
+			b.typingAt = b.cursor
+		}
+		if err == nil && b.validateOn == ENTRY_VALIDATE_ON_CHANGE && len(b.validators) > 0 {
+			b.Validate()
+		}
+		b.updateSuggestions()
+		return true
 	}
 	return false
 }
 
+/*
+Move the cursor. If shift is held the selection is extended from where it started (the anchor)
+otherwise the selection is cleared.
+*/
+func (b *SDL_Entry) moveCursorSelecting(i int) {
+	b.screenDataLock.Lock()
+	defer b.screenDataLock.Unlock()
+	b.closePopups()
+	if !b.shiftKeyDown {
+		b.ClearSelection()
+		b.setCursorNoLock(i)
+		return
+	}
+	if b.selectAnchor < 0 {
+		b.selectAnchor = b.cursor
+	}
+	b.setCursorNoLock(i)
+	from, too := b.selectAnchor, b.cursor
+	if too < from {
+		from, too = too, from
+	}
+	if from < too {
+		b.selectCharFrom = from
+		b.selectCharToo = too - 1
+	} else {
+		b.selectCharFrom = -1
+		b.selectCharToo = -1
+	}
+}
+
+/*
+Select all of the text and put the cursor at the end
+*/
+func (b *SDL_Entry) SelectAll() {
+	b.screenDataLock.Lock()
+	defer b.screenDataLock.Unlock()
+	b.ClearSelection()
+	if b.textLen > 0 {
+		b.selectAnchor = 0
+		b.selectCharFrom = 0
+		b.selectCharToo = b.textLen - 1
+	}
+	b.setCursorNoLock(b.textLen)
+}
+
+func (b *SDL_Entry) hasSelection() bool {
+	return b.selectCharFrom >= 0 && b.selectCharToo >= b.selectCharFrom && b.selectCharFrom < b.textLen
+}
+
+/*
+The selection as a slice range [from:too] of the runes
+*/
+func (b *SDL_Entry) selectionRange() (int, int) {
+	too := b.selectCharToo + 1
+	if too > b.textLen {
+		too = b.textLen
+	}
+	return b.selectCharFrom, too
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+/*
+Position of the start of the word before pos. Used by Ctrl+Left and Ctrl+Backspace
+*/
+func (b *SDL_Entry) wordStartRev(pos int) int {
+	if pos > b.textLen {
+		pos = b.textLen
+	}
+	for pos > 0 && !isWordRune(b.runes[pos-1]) {
+		pos--
+	}
+	for pos > 0 && isWordRune(b.runes[pos-1]) {
+		pos--
+	}
+	return pos
+}
+
+/*
+Position of the start of the word after pos. Used by Ctrl+Right and Ctrl+Delete
+*/
+func (b *SDL_Entry) wordStartFwd(pos int) int {
+	if pos < 0 {
+		pos = 0
+	}
+	for pos < b.textLen && isWordRune(b.runes[pos]) {
+		pos++
+	}
+	for pos < b.textLen && !isWordRune(b.runes[pos]) {
+		pos++
+	}
+	return pos
+}
+
+/*
+Add validators. They are run in order and the first error is used.
+See SetValidateOn for when they are run. Validate can be called at any time.
+*/
+func (b *SDL_Entry) AddValidator(v ...SDL_Validator) {
+	b.validators = append(b.validators, v...)
+}
+
+func (b *SDL_Entry) ClearValidators() {
+	b.validators = nil
+	b.setErrorMessage(nil)
+}
+
+/*
+ENTRY_VALIDATE_ON_CHANGE validates after every change. ENTRY_VALIDATE_ON_FINISH only validates on Return and
+when focus is lost. Both validate on Return and when focus is lost.
+*/
+func (b *SDL_Entry) SetValidateOn(on ENTRY_VALIDATE_ON) {
+	b.validateOn = on
+}
+
+func (b *SDL_Entry) GetValidateOn() ENTRY_VALIDATE_ON {
+	return b.validateOn
+}
+
+/*
+Run the validators against the text and set the error state and message. Returns the first error or nil.
+*/
+func (b *SDL_Entry) Validate() error {
+	err := b.validate()
+	b.setErrorMessage(err)
+	b.validateFailed = err != nil
+	return err
+}
+
+func (b *SDL_Entry) validate() error {
+	for _, v := range b.validators {
+		if err := v(b.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+The message from the last failed validator or onChange error. "" if not in error.
+Can be drawn under the field or shown as a tool tip.
+*/
+func (b *SDL_Entry) GetErrorMessage() string {
+	return b.errorMessage
+}
+
+/*
+Set the error from onChange. An onChange error is cleared by the next change onChange accepts.
+A validator error is not. In ENTRY_VALIDATE_ON_FINISH mode it stays until the next finish.
+*/
+func (b *SDL_Entry) setChangeError(err error) {
+	if err != nil || !b.validateFailed {
+		b.setErrorMessage(err)
+	}
+}
+
+func (b *SDL_Entry) setErrorMessage(err error) {
+	b.validateFailed = false
+	if err == nil {
+		b.errorMessage = ""
+	} else {
+		b.errorMessage = err.Error()
+	}
+	b.SetError(err != nil)
+}
+
 func (b *SDL_Entry) SetCursor(i int) {
 	b.screenDataLock.Lock()
 	defer b.screenDataLock.Unlock()
 	b.setCursorNoLock(i)
 }
 
-func (b *SDL_Entry) pushHistory(val string) {
-	if len(b.history) > 0 {
-		if (b.history)[len(b.history)-1] == val {
-			return
+func (b *SDL_Entry) pushHistory(edit *sdl_EntryEdit) {
+	if b.masked || b.undoMax <= 0 {
+		return
+	}
+	b.history = append(b.history, edit)
+	if len(b.history) > b.undoMax {
+		b.history = b.history[len(b.history)-b.undoMax:]
+	}
+}
+
+func (b *SDL_Entry) editState() *sdl_EntryEdit {
+	return &sdl_EntryEdit{text: b.text, cursor: b.cursor, selectFrom: b.selectCharFrom, selectToo: b.selectCharToo}
+}
+
+/*
+Set the max number of undo steps. 0 (or less) disables undo.
+*/
+func (b *SDL_Entry) SetUndoMax(max int) {
+	if max < 0 {
+		max = 0
+	}
+	b.undoMax = max
+	if len(b.history) > max {
+		b.history = b.history[len(b.history)-max:]
+	}
+}
+
+func (b *SDL_Entry) GetUndoMax() int {
+	return b.undoMax
+}
+
+func (b *SDL_Entry) ClearUndo() {
+	b.history = nil
+	b.redo = nil
+	b.typingAt = -1
+}
+
+func (b *SDL_Entry) CanUndo() bool {
+	return len(b.history) > 0
+}
+
+func (b *SDL_Entry) CanRedo() bool {
+	return len(b.redo) > 0
+}
+
+/*
+Undo the last edit (Ctrl+Z). onChange is called with ENTRY_EVENT_UNDO.
+*/
+func (b *SDL_Entry) Undo() bool {
+	b.keyPressLock.Lock()
+	defer b.keyPressLock.Unlock()
+	return b.undoNoLock()
+}
+
+/*
+Redo the last undone edit (Ctrl+Y or Ctrl+Shift+Z). onChange is called with ENTRY_EVENT_REDO.
+*/
+func (b *SDL_Entry) Redo() bool {
+	b.keyPressLock.Lock()
+	defer b.keyPressLock.Unlock()
+	return b.redoNoLock()
+}
+
+func (b *SDL_Entry) undoNoLock() bool {
+	return b.restoreEdit(&b.history, &b.redo, ENTRY_EVENT_UNDO)
+}
+
+func (b *SDL_Entry) redoNoLock() bool {
+	return b.restoreEdit(&b.redo, &b.history, ENTRY_EVENT_REDO)
+}
+
+/*
+Pop a state from one stack, push the current state on to the other and restore the popped state.
+If onChange rejects the change the stacks are left as they were.
+*/
+func (b *SDL_Entry) restoreEdit(from, too *[]*sdl_EntryEdit, onChangeType ENTRY_EVENT_TYPE) bool {
+	if len(*from) == 0 || b.readOnly {
+		return false
+	}
+	edit := (*from)[len(*from)-1]
+	newValue := edit.text
+	var err error
+	if b.onChange != nil {
+		newValue, err = b.onChange(b.text, newValue, onChangeType)
+		b.setChangeError(err)
+		if newValue == b.text {
+			return false
 		}
 	}
-	b.history = append(b.history, val)
+	*from = (*from)[0 : len(*from)-1]
+	*too = append(*too, b.editState())
+	b.typingAt = -1
+	b.SetText(newValue)
+	if err == nil && b.validateOn == ENTRY_VALIDATE_ON_CHANGE && len(b.validators) > 0 {
+		b.Validate()
+	}
+	b.screenDataLock.Lock()
+	defer b.screenDataLock.Unlock()
+	b.setCursorNoLock(edit.cursor)
+	if newValue == edit.text {
+		b.selectCharFrom = edit.selectFrom
+		b.selectCharToo = edit.selectToo
+	}
+	return true
 }
 
 func (b *SDL_Entry) setCursorNoLock(i int) {
 	if b.IsFocused() {
-		if i < 0 {
-			i = 0
-		}
-		if i >= b.textLen {
-			i = b.textLen
-			b.cursorAtEnd = true
-		} else {
-			b.cursorAtEnd = false
-		}
+		b.placeCursorNoLock(i)
+	}
+}
 
-		li := b.leadin
-		lo := b.leadout
-		span := lo - li
+/*
+Move the cursor even if the entry does not have the focus. For example to drop text where the mouse is.
+*/
+func (b *SDL_Entry) placeCursorNoLock(i int) {
+	if i < 0 {
+		i = 0
+	}
+	if i >= b.textLen {
+		i = b.textLen
+		b.cursorAtEnd = true
+	} else {
+		b.cursorAtEnd = false
+	}
+	b.cursor = i
+	b.showCursor = true
+}
 
-		if i > lo && !b.cursorAtEnd {
-			li = li + 1
+/*
+The text is drawn between textLeft and textRight. Pixel positions in the text (offsets in screenData)
+start at 0. Screen x is textLeft - scrollX + the offset.
+*/
+func (b *SDL_Entry) textLeft() int32 {
+	return b.x + b.indent
+}
+
+func (b *SDL_Entry) textRight() int32 {
+	r := b.x + b.w - ENTRY_CURSOR_WIDTH
+	if b.hasRevealButton() {
+		r = r - b.h
+	}
+	return r
+}
+
+func (b *SDL_Entry) textWidthNoLock() int32 {
+	var w int32
+	for disp := b.screenData; disp != nil; disp = disp.next {
+		w = disp.offset + disp.width
+	}
+	return w
+}
+
+/*
+Pixel position in the text of the char at rune position i. The width of the text if i is at the end.
+*/
+func (b *SDL_Entry) runeXNoLock(i int) int32 {
+	var w int32
+	for disp := b.screenData; disp != nil; disp = disp.next {
+		if disp.pos == i {
+			return disp.offset
 		}
-		if i < li {
-			li = li - 1
+		w = disp.offset + disp.width
+	}
+	return w
+}
+
+/*
+The rune position nearest to screen x. Used to place the cursor with the mouse.
+*/
+func (b *SDL_Entry) posAtNoLock(x int32) int {
+	tx := x - b.textLeft() + b.scrollX
+	for disp := b.screenData; disp != nil; disp = disp.next {
+		if tx < disp.offset+(disp.width/2) {
+			return disp.pos
 		}
-		b.leadin = li
-		b.leadout = li + span
-		b.cursor = i
 	}
+	return b.textLen
+}
+
+func (b *SDL_Entry) clampScrollNoLock() {
+	max := b.textWidthNoLock() - (b.textRight() - b.textLeft())
+	if b.scrollX > max {
+		b.scrollX = max
+	}
+	if b.scrollX < 0 {
+		b.scrollX = 0
+	}
+}
+
+func (b *SDL_Entry) scrollToCursorNoLock() {
+	cx := b.runeXNoLock(b.cursor)
+	if cx < b.scrollX {
+		b.scrollX = cx
+	}
+	if cx > b.scrollX+(b.textRight()-b.textLeft()) {
+		b.scrollX = cx - (b.textRight() - b.textLeft())
+	}
+	b.clampScrollNoLock()
+}
+
+/*
+Select from where the drag started to the mouse. The mouse x is kept inside the text area.
+Draw scrolls the text while the mouse is past an edge.
+*/
+func (b *SDL_Entry) dragSelectNoLock() {
+	x := b.dragX
+	if x < b.textLeft() {
+		x = b.textLeft()
+	}
+	if x > b.textRight() {
+		x = b.textRight()
+	}
+	b.setCursorNoLock(b.posAtNoLock(x))
+	b.showCursor = false
+	from, too := b.dragAnchor, b.cursor
+	if too < from {
+		from, too = too, from
+	}
+	b.selectAnchor = b.dragAnchor
+	if from < too {
+		b.selectCharFrom = from
+		b.selectCharToo = too - 1
+	} else {
+		b.selectCharFrom = -1
+		b.selectCharToo = -1
+	}
+}
+
+/*
+Called each frame while drag selecting. Scroll faster the further the mouse is past the edge.
+*/
+func (b *SDL_Entry) dragScrollNoLock() {
+	if b.dragX < b.textLeft() {
+		b.scrollX = b.scrollX - (ENTRY_DRAG_SCROLL_MIN + (b.textLeft()-b.dragX)/4)
+	} else if b.dragX > b.textRight() {
+		b.scrollX = b.scrollX + (ENTRY_DRAG_SCROLL_MIN + (b.dragX-b.textRight())/4)
+	} else {
+		return
+	}
+	b.clampScrollNoLock()
+	b.dragSelectNoLock()
 }
 
 func (b *SDL_Entry) MoveCursor(i int) {
@@ -294,19 +997,23 @@ func (b *SDL_Entry) GetText() string {
 }
 
 func (b *SDL_Entry) GetSelectedText() string {
+	if b.masked {
+		return ""
+	}
 	if b.selectCharFrom < 0 || b.selectCharToo < 0 {
 		return ""
 	}
 	if b.selectCharFrom > b.selectCharToo {
 		return ""
 	}
-	var sb strings.Builder
-	for i, c := range b.text {
-		if i >= b.selectCharFrom && i <= b.selectCharToo {
-			sb.WriteRune(c)
-		}
+	too := b.selectCharToo + 1
+	if too > b.textLen {
+		too = b.textLen
 	}
-	return sb.String()
+	if b.selectCharFrom >= too {
+		return ""
+	}
+	return string(b.runes[b.selectCharFrom:too])
 }
 
 func (b *SDL_Entry) SetSelectedTextBounds(from, too uint) error {
@@ -316,12 +1023,14 @@ func (b *SDL_Entry) SetSelectedTextBounds(from, too uint) error {
 	}
 	b.selectCharFrom = int(from)
 	b.selectCharToo = int(too)
+	b.selectAnchor = -1
 	return nil
 }
 
 func (b *SDL_Entry) ClearSelection() {
 	b.selectCharFrom = -1
 	b.selectCharToo = -1
+	b.selectAnchor = -1
 }
 
 func (b *SDL_Entry) selectAtCursor(clicks int) bool {
@@ -338,155 +1047,611 @@ func (b *SDL_Entry) selectAtCursor(clicks int) bool {
 	return true
 }
 
+/*
+In overwrite mode typed text replaces the chars at the cursor otherwise it is inserted.
+A selection is always replaced. Returns the new text and where the cursor should go.
+*/
+func (b *SDL_Entry) typeAtCursor(text string) (string, int) {
+	if b.inputMask != nil {
+		return b.replaceSelection(text)
+	}
+	if b.overwrite && !b.hasSelection() {
+		too := b.cursor + utf8.RuneCountInString(text)
+		if too > b.textLen {
+			too = b.textLen
+		}
+		text = b.fitMaxLength(text, too-b.cursor)
+		n := utf8.RuneCountInString(text)
+		too = b.cursor + n
+		if too > b.textLen {
+			too = b.textLen
+		}
+		return string(b.runes[0:b.cursor]) + text + string(b.runes[too:]), b.cursor + n
+	}
+	return b.replaceSelection(text)
+}
+
+/*
+Replace the selected text (if any) with text otherwise insert it at the cursor.
+Returns the new text and where the cursor should go.
+*/
+func (b *SDL_Entry) replaceSelection(text string) (string, int) {
+	if b.inputMask != nil {
+		runes, from := b.runes, b.cursor
+		if b.hasSelection() {
+			var too int
+			from, too = b.selectionRange()
+			runes = b.inputMask.clear(runes, from, too)
+		}
+		runes, pos := b.inputMask.fill(runes, from, text)
+		if text == "" {
+			pos = from
+		}
+		return string(runes), pos
+	}
+	if b.hasSelection() {
+		from, too := b.selectionRange()
+		text = b.fitMaxLength(text, too-from)
+		n := utf8.RuneCountInString(text)
+		return string(b.runes[0:from]) + text + string(b.runes[too:]), from + n
+	}
+	text = b.fitMaxLength(text, 0)
+	return b.insertAtCursor(text), b.cursor + utf8.RuneCountInString(text)
+}
+
+/*
+Cut text so the result is not longer than the max length when 'replaced' chars are removed from the text.
+*/
+func (b *SDL_Entry) fitMaxLength(text string, replaced int) string {
+	if b.maxLength <= 0 {
+		return text
+	}
+	room := b.maxLength - (b.textLen - replaced)
+	if room <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	if len(runes) > room {
+		return string(runes[0:room])
+	}
+	return text
+}
+
+/*
+Max length of the text in runes. Typing stops and pasted text is cut at the max length. 0 for no limit.
+SetText is not limited.
+*/
+func (b *SDL_Entry) SetMaxLength(max int) {
+	b.maxLength = max
+}
+
+func (b *SDL_Entry) GetMaxLength() int {
+	return b.maxLength
+}
+
+/*
+Make this a number entry. Each mouse wheel step adds (away from the user) or subtracts step.
+The number is shown with as many decimal places as step. 0 (the default) turns it off.
+onChange is called with ENTRY_EVENT_SCROLL.
+*/
+func (b *SDL_Entry) SetScrollStep(step float64) {
+	b.scrollStep = step
+}
+
+func (b *SDL_Entry) GetScrollStep() float64 {
+	return b.scrollStep
+}
+
+/*
+Step the number in the entry. Does nothing (returns false) if the text is not a number
+*/
+func (b *SDL_Entry) Scroll(dx, dy int32, precise float32) bool {
+	if !b.IsEnabled() || b.readOnly || b.inputMask != nil || b.scrollStep == 0 || dy == 0 {
+		return false
+	}
+	b.keyPressLock.Lock()
+	defer b.keyPressLock.Unlock()
+	v := 0.0
+	s := strings.TrimSpace(b.text)
+	if s != "" {
+		var err error
+		v, err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			return false
+		}
+	}
+	step := strconv.FormatFloat(b.scrollStep, 'f', -1, 64)
+	places := 0
+	if i := strings.IndexByte(step, '.'); i >= 0 {
+		places = len(step) - i - 1
+	}
+	newValue := strconv.FormatFloat(v+(float64(dy)*b.scrollStep), 'f', places, 64)
+	b.applyChange(b.text, newValue, ENTRY_EVENT_SCROLL, utf8.RuneCountInString(newValue))
+	return true
+}
+
+/*
+Unless SetDropTarget has been called an editable entry accepts dropped text and file paths.
+An entry with an input mask only accepts text.
+*/
+func (b *SDL_Entry) AcceptsDrop(p *SDL_DragPayload) bool {
+	if b.onDrop != nil {
+		return b.SDL_WidgetBase.AcceptsDrop(p)
+	}
+	if !b.IsEnabled() || !b.IsVisible() || b.readOnly {
+		return false
+	}
+	return p.kind == DRAG_PAYLOAD_TEXT || (p.kind == DRAG_PAYLOAD_FILE && b.inputMask == nil)
+}
+
+/*
+A file path replaces the text. Text is inserted where it is dropped. Both are cut at the max length.
+onChange is called with ENTRY_EVENT_DROP.
+*/
+func (b *SDL_Entry) Drop(p *SDL_DragPayload, x, y int32) bool {
+	if b.onDrop != nil {
+		return b.SDL_WidgetBase.Drop(p, x, y)
+	}
+	if !b.AcceptsDrop(p) {
+		return false
+	}
+	b.keyPressLock.Lock()
+	defer b.keyPressLock.Unlock()
+	if p.kind == DRAG_PAYLOAD_FILE {
+		text := b.fitMaxLength(p.text, b.textLen)
+		return b.applyChange(b.text, text, ENTRY_EVENT_DROP, utf8.RuneCountInString(text))
+	}
+	b.screenDataLock.Lock()
+	b.ClearSelection()
+	b.placeCursorNoLock(b.posAtNoLock(x))
+	b.screenDataLock.Unlock()
+	newValue, cursorAfter := b.typeAtCursor(p.text)
+	return b.applyChange(b.text, newValue, ENTRY_EVENT_DROP, cursorAfter)
+}
+
+/*
+Shown dimmed when the entry is empty and does not have focus.
+*/
+func (b *SDL_Entry) SetPlaceholderText(text string) {
+	b.placeholderText = text
+}
+
+func (b *SDL_Entry) GetPlaceholderText() string {
+	return b.placeholderText
+}
+
+/*
+A read only entry can take focus and its text can be selected and copied but not changed.
+It is drawn with the disabled background. The text and border are drawn as normal.
+*/
+func (b *SDL_Entry) SetReadOnly(readOnly bool) {
+	changed := readOnly != b.readOnly
+	b.readOnly = readOnly
+	if readOnly {
+		b.composition = nil
+		b.closePopups()
+	}
+	// Text input and the virtual keyboard are only active for an editable focused entry
+	if changed && b.IsFocused() {
+		kb := GetResourceInstance().GetVirtualKeyboard()
+		if kb != nil {
+			kb.focusChanged(b, !readOnly)
+		}
+		if readOnly {
+			GetResourceInstance().stopTextInput(b)
+		} else {
+			b.imeRect = sdl.Rect{}
+			GetResourceInstance().startTextInput(b)
+		}
+	}
+}
+
+func (b *SDL_Entry) IsReadOnly() bool {
+	return b.readOnly
+}
+
+func (b *SDL_Entry) GetBackground() *sdl.Color {
+	if b.readOnly && b.IsEnabled() && b.background == nil {
+		return GetResourceInstance().GetColour(WIDGET_COLOUR_INDEX_DISABLE, WIDGET_COLOUR_STYLE_BG)
+	}
+	return b.SDL_WidgetBase.GetBackground()
+}
+
+/*
+In overwrite mode typed chars replace the char at the cursor and the cursor is drawn under the char.
+*/
+func (b *SDL_Entry) SetOverwrite(overwrite bool) {
+	b.overwrite = overwrite
+	b.Invalid(true)
+}
+
+func (b *SDL_Entry) IsOverwrite() bool {
+	return b.overwrite
+}
+
+/*
+Remove the chars from (inclusive) to too (exclusive). With an input mask the slots are emptied instead.
+*/
+func (b *SDL_Entry) removeRange(from, too int) string {
+	if b.inputMask != nil {
+		return string(b.inputMask.clear(b.runes, from, too))
+	}
+	return string(b.runes[0:from]) + string(b.runes[too:])
+}
+
+/*
+The first editable position at or after i. With an input mask literals are skipped.
+*/
+func (b *SDL_Entry) nextEditPos(i int) int {
+	if b.inputMask != nil {
+		return b.inputMask.nextSlot(i)
+	}
+	if i > b.textLen {
+		return b.textLen
+	}
+	return i
+}
+
+/*
+The last editable position before i. -1 if none. With an input mask literals are skipped.
+*/
+func (b *SDL_Entry) prevEditPos(i int) int {
+	if b.inputMask != nil {
+		return b.inputMask.prevSlot(i)
+	}
+	if i > b.textLen {
+		return b.textLen - 1
+	}
+	return i - 1
+}
+
+/*
+Set an input mask for a fixed format like ENTRY_INPUT_MASK_DATE. See widget-inputmask.go for the mask chars.
+The text is always the full mask with placeholders in the empty slots. Use GetRawText or GetFormattedText
+for the value. "" removes the mask.
+*/
+func (b *SDL_Entry) SetInputMask(mask string) error {
+	if mask == "" {
+		b.inputMask = nil
+		return nil
+	}
+	m, err := newInputMask(mask, b.inputMaskPh)
+	if err != nil {
+		return err
+	}
+	b.inputMask = m
+	b.ClearUndo()
+	b.SetText(b.text)
+	b.SetCursor(m.nextSlot(0))
+	return nil
+}
+
+func (b *SDL_Entry) GetInputMask() string {
+	if b.inputMask == nil {
+		return ""
+	}
+	return b.inputMask.mask
+}
+
+/*
+The char drawn in the empty slots of an input mask. Default is ENTRY_DEFAULT_INPUT_MASK_PLACEHOLDER
+*/
+func (b *SDL_Entry) SetInputMaskPlaceholder(r rune) {
+	old := b.inputMaskPh
+	b.inputMaskPh = r
+	if b.inputMask != nil {
+		b.inputMask.placeholder = r
+		runes := []rune(b.text)
+		for i := range runes {
+			if b.inputMask.isSlot(i) && runes[i] == old {
+				runes[i] = r
+			}
+		}
+		b.SetText(string(runes))
+	}
+}
+
+/*
+With an input mask only the chars typed in to the slots. Otherwise the text.
+*/
+func (b *SDL_Entry) GetRawText() string {
+	if b.inputMask == nil {
+		return b.text
+	}
+	return b.inputMask.raw(b.runes)
+}
+
+/*
+With an input mask the text without placeholders. Otherwise the text.
+*/
+func (b *SDL_Entry) GetFormattedText() string {
+	if b.inputMask == nil {
+		return b.text
+	}
+	return b.inputMask.formatted(b.runes)
+}
+
+/*
+True if there is no input mask or all its slots are filled
+*/
+func (b *SDL_Entry) IsInputMaskComplete() bool {
+	if b.inputMask == nil {
+		return true
+	}
+	return b.inputMask.complete(b.runes)
+}
+
+/*
+Suggest completions in a popup under the entry. The provider is called with the text before the cursor
+after each edit. Up and Down select a suggestion. Tab accepts the selected (or first) suggestion,
+Enter accepts the selected suggestion and Escape closes the popup. The rest of the suggestion is shown
+dimmed after the text. See PathAutoCompleteProvider. nil removes auto complete.
+*/
+func (b *SDL_Entry) SetAutoComplete(provider func(string) []string) {
+	if provider == nil {
+		b.autoComplete = nil
+		return
+	}
+	b.autoComplete = newAutoComplete(provider)
+}
+
+/*
+The max number of rows in the popup. Default is AUTO_COMPLETE_DEFAULT_ROWS
+*/
+func (b *SDL_Entry) SetAutoCompleteRows(rows int) {
+	if b.autoComplete != nil && rows > 0 {
+		b.autoComplete.rows = rows
+	}
+}
+
+func (b *SDL_Entry) IsSuggesting() bool {
+	return b.autoComplete != nil && b.autoComplete.isOpen()
+}
+
+func (b *SDL_Entry) GetSuggestions() []string {
+	if b.autoComplete == nil {
+		return []string{}
+	}
+	return b.autoComplete.items
+}
+
+func (b *SDL_Entry) updateSuggestions() {
+	if b.autoComplete != nil && !b.masked && b.inputMask == nil && b.IsFocused() {
+		b.autoComplete.update(string(b.runes[0:b.cursor]))
+	}
+}
+
+/*
+Replace the text before the cursor with the suggestion. If the suggestion has suggestions of its own
+(for example a dir) the popup stays open.
+*/
+func (b *SDL_Entry) acceptSuggestion() bool {
+	s := b.autoComplete.selected()
+	if s == "" {
+		return false
+	}
+	b.autoComplete.close()
+	s = b.fitMaxLength(s, b.cursor)
+	newValue := s + string(b.runes[b.cursor:])
+	if !b.applyChange(b.text, newValue, ENTRY_EVENT_INSERT, utf8.RuneCountInString(s)) {
+		b.updateSuggestions()
+	}
+	return true
+}
+
+/*
+Show the clipboard history in a popup (Ctrl+Shift+V). Accepting an item pastes it.
+Returns false if the clipboard does not keep a history (see NewHistoryClipboard) or it is empty.
+*/
+func (b *SDL_Entry) ShowPasteHistory() bool {
+	ch, ok := GetResourceInstance().GetClipboard().(SDL_ClipboardHistory)
+	if !ok || b.readOnly || !b.IsFocused() {
+		return false
+	}
+	h := ch.GetHistory()
+	if len(h) == 0 {
+		return false
+	}
+	if b.autoComplete != nil {
+		b.autoComplete.close()
+	}
+	if b.pasteHistory == nil {
+		b.pasteHistory = newAutoComplete(nil)
+	}
+	b.pasteHistory.show(h)
+	return true
+}
+
+func (b *SDL_Entry) IsPasteHistoryOpen() bool {
+	return b.pasteHistory != nil && b.pasteHistory.isOpen()
+}
+
+/*
+The popup that is open. The paste history or the auto complete suggestions. nil if none are open.
+*/
+func (b *SDL_Entry) openPopup() *sdl_AutoComplete {
+	if b.IsPasteHistoryOpen() {
+		return b.pasteHistory
+	}
+	if b.IsSuggesting() {
+		return b.autoComplete
+	}
+	return nil
+}
+
+func (b *SDL_Entry) closePopups() {
+	if b.autoComplete != nil {
+		b.autoComplete.close()
+	}
+	if b.pasteHistory != nil {
+		b.pasteHistory.close()
+	}
+}
+
+func (b *SDL_Entry) acceptPopup(p *sdl_AutoComplete) bool {
+	if p != b.pasteHistory {
+		return b.acceptSuggestion()
+	}
+	s := p.selected()
+	p.close()
+	newValue, cursorAfter := b.replaceSelection(s)
+	b.applyChange(b.text, newValue, ENTRY_EVENT_INSERT, cursorAfter)
+	return true
+}
+
+func (b *SDL_Entry) popupRect(p *sdl_AutoComplete) *sdl.Rect {
+	return p.rect(b.x, b.y+b.h, b.w, b.h)
+}
+
+func (b *SDL_Entry) insidePopup(x, y int32) bool {
+	p := b.openPopup()
+	return b.IsVisible() && p != nil && isInsideRect(x, y, b.popupRect(p))
+}
+
 func (b *SDL_Entry) insertAtCursor(text string) string {
 	if b.cursor < b.textLen {
-		return fmt.Sprintf("%s%s%s", b.text[0:b.cursor], text, b.text[b.cursor:])
+		return string(b.runes[0:b.cursor]) + text + string(b.runes[b.cursor:])
 	} else {
 		return fmt.Sprintf("%s%s", b.text, text)
 	}
 }
 
 func (b *SDL_Entry) Click(md *SDL_MouseData) bool {
+	if md.GetButtons() == sdl.BUTTON_RIGHT || md.GetButtons() == sdl.BUTTON_MIDDLE {
+		// For the right and middle click listeners
+		return b.SDL_WidgetBase.Click(md)
+	}
 	if b.IsEnabled() {
 		b.keyPressLock.Lock()
 		defer b.keyPressLock.Unlock()
 
+		if p := b.openPopup(); p != nil && !md.IsDragging() {
+			i := p.itemAt(b.popupRect(p), b.h, md.GetX(), md.GetY())
+			if i >= 0 {
+				p.index = i
+				return b.acceptPopup(p)
+			}
+		}
+
+		if b.hasRevealButton() && !md.IsDragging() && isInsideRect(md.GetX(), md.GetY(), b.revealButtonRect()) {
+			b.Reveal(!b.revealed)
+			return true
+		}
+
 		if md.GetClickCount() > 1 {
+			b.pressForListeners(md)
 			return b.selectAtCursor(md.GetClickCount())
 		}
 
 		/*
-			Is currently dragging so get dragged from (start of draggings)
-			Set the flag
+			Is currently dragging. Select from where the drag started (md x) to the mouse
 		*/
 		if md.IsDragging() {
+			// Selecting by dragging is not a click
+			b.pressed = false
+			b.pressedOn = false
+			b.screenDataLock.Lock()
+			defer b.screenDataLock.Unlock()
 			if !b.dragging {
-				b.dragFrom = md.GetDraggingX()
-				b.dragTo = b.dragFrom
+				b.dragAnchor = b.posAtNoLock(md.GetX())
 				b.dragging = true
-			} else {
-				b.dragTo = md.GetDraggingX()
 			}
+			b.dragX = md.GetDraggingX()
+			b.dragSelectNoLock()
 			return true
-		} else {
-			// If not dragging then clear flag
-			b.dragging = false
 		}
+		// If not dragging then clear flag
+		b.dragging = false
 
 		/*
-			Done dragging so work out what is to be selected
+			Done dragging. The selection was made while dragging
 		*/
 		if md.IsDragged() {
-			go func() {
-				b.screenDataLock.Lock()
-				defer b.screenDataLock.Unlock()
-				selTo := b.dragTo
-				selFrom := b.dragFrom
-
-				if selTo < selFrom {
-					temp := selFrom
-					selFrom = selTo
-					selTo = temp
-				}
-
-				linked := b.screenData
-				if linked == nil {
-					return
-				}
-
-				/*
-					Go through each image and check from and too are inside.
-				*/
-				sel := false
-				for linked != nil {
-					if linked.Inside(selFrom) && !sel {
-						// Start selecting
-						b.selectCharFrom = linked.pos
-						b.selectCharToo = linked.pos
-						sel = true
-					}
-					if linked.Inside(selTo) && sel {
-						// End selecting
-						b.selectCharToo = linked.pos
-						sel = false
-					}
-					linked = linked.next
-				}
-			}()
 			return true
 		}
 
 		/*
 			Clicked on widget so work out where to set the cursor
 		*/
-		go func() {
-			b.screenDataLock.Lock()
-			defer b.screenDataLock.Unlock()
-			linked := b.screenData
-			if linked == nil {
-				return
-			}
-			notFound := true
-			for linked != nil {
-				if linked.Inside(md.GetX()) {
-					b.setCursorNoLock(linked.pos)
-					notFound = false
-					break
-				}
-				linked = linked.next
-			}
-			if notFound {
-				b.setCursorNoLock(b.textLen)
-			}
-		}()
+		b.screenDataLock.Lock()
+		defer b.screenDataLock.Unlock()
+		b.ClearSelection()
+		b.setCursorNoLock(b.posAtNoLock(md.GetX()))
+		b.pressForListeners(md)
 	}
 	return false
 }
 
+/*
+Left clicks and double clicks are passed to the click listeners when the mouse is released, as for other widgets.
+The entry has already used them to place the cursor or select.
+*/
+func (b *SDL_Entry) pressForListeners(md *SDL_MouseData) {
+	if md.IsDown() && b.hasClickHandler(md) {
+		b.pressed = true
+		b.pressedOn = true
+	}
+}
+
+/*
+The height the text is drawn at. Rune widths are scaled to it.
+*/
+func (b *SDL_Entry) textHeight() int32 {
+	return b.h - int32(float32(b.h)/6)
+}
+
+/*
+Build the rune positions (screenData) if the text has changed and scroll to keep the cursor in view.
+Done at the start of Draw. Returns an error if the rune textures could not be made.
+*/
+func (b *SDL_Entry) layoutNoLock(renderer *sdl.Renderer, font *ttf.Font) error {
+	if b._invalid {
+		b.Invalid(false)
+		fg := b.GetForeground()
+		text := b.displayText()
+		err := GetResourceInstance().UpdateTextureCachedRunes(renderer, font, fg, text)
+		if err != nil {
+			return err
+		}
+		//
+		// Scale the text to fit the height but keep the aspect ration the same so we know the width of each char.
+		// Offsets start at 0 so they are not changed by scrolling.
+		//
+		b.screenData = GetResourceInstance().GetScaledTextureListFromCachedRunesLinked(text, fg, 0, b.textHeight())
+	}
+
+	//
+	// Scroll by pixels. Follow the mouse when drag selecting otherwise keep the cursor visible
+	//
+	if b.dragging && b.IsFocused() {
+		b.dragScrollNoLock()
+	}
+	if b.showCursor {
+		b.showCursor = false
+		b.scrollToCursorNoLock()
+	} else {
+		b.clampScrollNoLock()
+	}
+	return nil
+}
+
 func (b *SDL_Entry) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
 	if b.IsVisible() {
 		b.screenDataLock.Lock()
 		defer b.screenDataLock.Unlock()
 
-		var err error
-
-		tx := b.x + b.indent
-		th := b.h - int32(float32(b.h)/6)
+		left := b.textLeft()
+		right := b.textRight()
+		th := b.textHeight()
 		ty := (b.h - th) / 2
 
-		if b._invalid {
-			b.Invalid(false)
-			fg := b.GetForeground()
-			err = GetResourceInstance().UpdateTextureCachedRunes(renderer, font, fg, b.text)
-			if err != nil {
-				renderer.SetDrawColor(255, 0, 0, 255)
-				renderer.DrawRect(&sdl.Rect{X: b.x, Y: b.y, W: b.w, H: b.h})
-				return nil
-			}
-			sd := GetResourceInstance().GetScaledTextureListFromCachedRunesLinked(b.text, fg, tx, th)
-			if sd == nil {
-				if err != nil {
-					renderer.SetDrawColor(255, 0, 0, 255)
-					renderer.DrawRect(&sdl.Rect{X: b.x, Y: b.y, W: b.w, H: b.h})
-					return nil
-				}
-			}
-			b.screenData = sd
-		}
-
-		screenData := b.screenData
-		// *******************************************************
-		// Find the number of chars thet can be displayed 'cc'
-		if b.leadin >= b.textLen { // Ensure leadin is not past the end
-			b.leadin = b.textLen - 1
-		}
-		if b.leadin < 0 { // Ensure leadin is not negative
-			b.leadin = 0
+		if b.revealed && (sdl.GetTicks64()-b.revealedAt) > ENTRY_REVEAL_MS {
+			b.revealNoLock(false)
 		}
 
-		screenData.SetAllVisible(false)
-		leadIn := screenData.Indexed(b.leadin)
-		if leadIn == nil {
+		if b.layoutNoLock(renderer, font) != nil {
+			renderer.SetDrawColor(255, 0, 0, 255)
+			renderer.DrawRect(&sdl.Rect{X: b.x, Y: b.y, W: b.w, H: b.h})
 			return nil
 		}
 
@@ -496,63 +1661,188 @@ func (b *SDL_Entry) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
 			renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
 			renderer.FillRect(&sdl.Rect{X: b.x, Y: b.y, W: b.w, H: b.h})
 		}
-		//
-		// If selectiing with the mouse then draw the background
-		//
-		if b.dragging && b.IsFocused() {
-			c := GetResourceInstance().GetCursorSelectColour()
-			renderer.SetDrawColor(c.R, c.G, c.B, c.A)
-			if b.dragFrom > b.dragTo {
-				renderer.FillRect(&sdl.Rect{X: b.dragTo, Y: b.y + 1, W: b.dragFrom - b.dragTo, H: b.h - 2})
-			} else {
-				renderer.FillRect(&sdl.Rect{X: b.dragFrom, Y: b.y + 1, W: b.dragTo - b.dragFrom, H: b.h - 2})
-			}
+
+		if b.textLen == 0 && !b.IsFocused() && b.placeholderText != "" {
+			b.drawPlaceholderText(renderer, font, th)
 		}
 
 		paintCursor := b.IsEnabled() && b.IsFocused() && (sdl.GetTicks64()%1000) > 300
 
 		//
-		// Scale the text to fit the height but keep the aspect ration the same so we know the width of each char
-		//   Need to use floats to prevent rounding
+		// Clip to the text area so chars scrolled part way out of view are cut at the edge
 		//
-		var rect *sdl.Rect
-		tx = b.x + b.indent
-		max := b.x + b.w
-		last := 0
-		disp := leadIn
-		for disp != nil && tx+disp.width < max {
-			last = disp.pos
+		clipRect := renderer.GetClipRect()
+		renderer.SetClipRect(&sdl.Rect{X: left, Y: b.y, W: right - left + ENTRY_CURSOR_WIDTH, H: b.h})
+
+		cursorAtEnd := b.cursorAtEnd || b.textLen == 0
+		cursorX := int32(-1)
+		cursorW := ENTRY_CURSOR_WIDTH
+		compW := int32(0) // Text after the cursor is moved right by the width of the IME composition
+		for disp := b.screenData; disp != nil; disp = disp.next {
+			tx := left - b.scrollX + disp.offset + compW
+			if !cursorAtEnd && disp.pos == b.cursor {
+				cursorX = tx
+				cursorW = disp.width
+				compW, cursorX = b.drawComposition(renderer, font, tx, b.y+ty, th, right)
+				tx = tx + compW
+			}
 			tw := disp.width
-			rect = &sdl.Rect{X: tx, Y: b.y + ty, W: tw, H: th}
-			disp.SetVisible(true)
-			if disp.pos >= b.selectCharFrom && disp.pos <= b.selectCharToo {
-				c := GetResourceInstance().GetCursorSelectColour()
-				renderer.SetDrawColor(c.R, c.G, c.B, c.A)
-				renderer.FillRect(rect)
+			disp.SetVisible(tx+tw > left && tx < right)
+			if disp.IsVisible() {
+				rect := &sdl.Rect{X: tx, Y: b.y + ty, W: tw, H: th}
+				if disp.pos >= b.selectCharFrom && disp.pos <= b.selectCharToo {
+					c := GetResourceInstance().GetCursorSelectColour()
+					renderer.SetDrawColor(c.R, c.G, c.B, c.A)
+					renderer.FillRect(rect)
+				}
+				renderer.Copy(disp.te.texture, nil, rect)
 			}
-			renderer.Copy(disp.te.texture, nil, rect)
-			if !b.cursorAtEnd && paintCursor && disp.pos == b.cursor {
+		}
+		if cursorAtEnd {
+			tx := left - b.scrollX + b.textWidthNoLock()
+			compW, cursorX = b.drawComposition(renderer, font, tx, b.y+ty, th, right)
+			if compW == 0 && !b.IsPasteHistoryOpen() && b.IsSuggesting() {
+				b.drawGhost(renderer, font, tx, b.y+ty, th, right)
+			}
+		}
+		if cursorX >= left && cursorX <= right {
+			if paintCursor {
 				c := GetResourceInstance().GetCursorInsertColour()
+				if cursorAtEnd {
+					c = GetResourceInstance().GetCursorAppendColour()
+				}
 				renderer.SetDrawColor(c.R, c.G, c.B, c.A)
-				renderer.FillRect(&sdl.Rect{X: tx, Y: b.y, W: 5, H: b.h})
+				if b.overwrite && len(b.composition) == 0 {
+					// Overwrite cursor is a bar under the char that will be replaced
+					renderer.FillRect(&sdl.Rect{X: cursorX, Y: b.y + b.h - 5, W: cursorW, H: 5})
+				} else {
+					renderer.FillRect(&sdl.Rect{X: cursorX, Y: b.y, W: ENTRY_CURSOR_WIDTH, H: b.h})
+				}
 			}
-			tx = tx + tw
-			disp = disp.next
+			b.updateIMERect(cursorX)
+		}
+		if clipRect.W > 0 && clipRect.H > 0 {
+			renderer.SetClipRect(&clipRect)
+		} else {
+			renderer.SetClipRect(nil)
 		}
-		b.leadout = last
-		if b.cursorAtEnd && paintCursor && tx < max {
-			c := GetResourceInstance().GetCursorAppendColour()
-			renderer.SetDrawColor(c.R, c.G, c.B, c.A)
-			renderer.FillRect(&sdl.Rect{X: tx, Y: b.y, W: 5, H: b.h})
+
+		if b.hasRevealButton() {
+			b.drawRevealButton(renderer)
 		}
 		if b.ShouldDrawBorder() {
 			bc := b.GetBorderColour()
 			renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
 			renderer.DrawRect(&sdl.Rect{X: b.x + 1, Y: b.y + 1, W: b.w - 2, H: b.h - 2})
 		}
+		if p := b.openPopup(); p != nil && b.IsFocused() {
+			p.draw(renderer, font, b.popupRect(p), b.h, b.widgetId, b.GetForeground(), b.GetBackground(), b.GetBorderColour())
+		}
 	}
 	return nil
 }
+
+func (b *SDL_Entry) drawPlaceholderText(renderer *sdl.Renderer, font *ttf.Font, th int32) {
+	fg := GetResourceInstance().GetColour(WIDGET_COLOUR_INDEX_DISABLE, WIDGET_COLOUR_STYLE_FG)
+	ct, err := GetResourceInstance().UpdateTextureFromString(renderer, fmt.Sprintf("entry:%d:placeholder", b.widgetId), b.placeholderText, font, fg)
+	if err != nil {
+		return
+	}
+	rw, sw, sh := ct.ScaledWidthHeight(th, b.w-b.indent-5)
+	renderer.Copy(ct.texture, &sdl.Rect{X: 0, Y: 0, W: rw, H: ct.h}, &sdl.Rect{X: b.x + b.indent, Y: b.y + (b.h-th)/2, W: sw, H: sh})
+}
+
+/*
+Draw the rest of the suggestion after the text in the disabled colour
+*/
+func (b *SDL_Entry) drawGhost(renderer *sdl.Renderer, font *ttf.Font, x, y, th, max int32) {
+	ghost := b.autoComplete.ghost()
+	if ghost == "" {
+		return
+	}
+	fg := GetResourceInstance().GetColour(WIDGET_COLOUR_INDEX_DISABLE, WIDGET_COLOUR_STYLE_FG)
+	err := GetResourceInstance().UpdateTextureCachedRunes(renderer, font, fg, ghost)
+	if err != nil {
+		return
+	}
+	tx := x
+	disp := GetResourceInstance().GetScaledTextureListFromCachedRunesLinked(ghost, fg, x, th)
+	for disp != nil && tx+disp.width < max {
+		renderer.Copy(disp.te.texture, nil, &sdl.Rect{X: tx, Y: y, W: disp.width, H: th})
+		tx = tx + disp.width
+		disp = disp.next
+	}
+}
+
+/*
+Draw the IME composition underlined at x. Returns the width drawn and the x of the IME cursor (compCursor)
+within it. The entry cursor is drawn there.
+*/
+func (b *SDL_Entry) drawComposition(renderer *sdl.Renderer, font *ttf.Font, x, y, th, max int32) (int32, int32) {
+	if len(b.composition) == 0 {
+		return 0, x
+	}
+	fg := b.GetForeground()
+	comp := b.displayComposition()
+	err := GetResourceInstance().UpdateTextureCachedRunes(renderer, font, fg, comp)
+	if err != nil {
+		return 0, x
+	}
+	tx := x
+	caretX := int32(-1)
+	disp := GetResourceInstance().GetScaledTextureListFromCachedRunesLinked(comp, fg, x, th)
+	for disp != nil && tx+disp.width < max {
+		if disp.pos == b.compCursor {
+			caretX = tx
+		}
+		renderer.Copy(disp.te.texture, nil, &sdl.Rect{X: tx, Y: y, W: disp.width, H: th})
+		tx = tx + disp.width
+		disp = disp.next
+	}
+	if caretX < 0 {
+		// At the end of the composition or past max
+		caretX = tx
+	}
+	renderer.SetDrawColor(fg.R, fg.G, fg.B, fg.A)
+	renderer.DrawLine(x, y+th-1, tx, y+th-1)
+	renderer.DrawLine(x, y+th-2, tx, y+th-2)
+	return tx - x, caretX
+}
+
+/*
+Tell the IME where the cursor is so the candidate list is shown next to it
+*/
+func (b *SDL_Entry) updateIMERect(cursorX int32) {
+	if b.IsFocused() {
+		r := sdl.Rect{X: cursorX, Y: b.y, W: 1, H: b.h}
+		if r != b.imeRect {
+			b.imeRect = r
+			sdl.SetTextInputRect(&r)
+		}
+	}
+}
+
+/*
+Draw an eye. Open (filled pupil) when revealed. Crossed out when masked.
+*/
+func (b *SDL_Entry) drawRevealButton(renderer *sdl.Renderer) {
+	r := b.revealButtonRect()
+	fg := b.GetForeground()
+	cx := r.X + (r.W / 2)
+	cy := r.Y + (r.H / 2)
+	rx := (r.W / 2) - (r.W / 6)
+	ry := r.H / 4
+	gfx.EllipseColor(renderer, cx, cy, rx, ry, *fg)
+	if b.revealed {
+		gfx.FilledCircleColor(renderer, cx, cy, ry-1, *fg)
+	} else {
+		gfx.CircleColor(renderer, cx, cy, ry-1, *fg)
+		gfx.LineColor(renderer, cx-rx, cy+ry, cx+rx, cy-ry, *fg)
+	}
+	bc := b.GetBorderColour()
+	renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
+	renderer.DrawLine(r.X, r.Y+2, r.X, r.Y+r.H-2)
+}
 func (b *SDL_Entry) Destroy() {
 	// Image cache takes care of all images!
 }
@@ -0,0 +1,526 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestEntryMasked(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetMasked(true)
+	e.SetFocused(true)
+	typeString(e, "abc")
+	assertString(t, "Real text", e.GetText(), "abc")
+	assertString(t, "Display text", e.displayText(), "•••")
+	assertString(t, "String", e.String(), "•••")
+
+	e.SetSelectedTextBounds(0, 2)
+	assertString(t, "Selected text", e.GetSelectedText(), "")
+	mc := useMemoryClipboard(t)
+	mc.SetText("clip")
+	ctrlKey(e, sdl.K_c)
+	s, _ := mc.GetText()
+	assertString(t, "Clipboard", s, "clip")
+
+	assertInt(t, "No history", len(e.history), 0)
+	ctrlKey(e, sdl.K_z)
+	assertString(t, "Undo ignored", e.GetText(), "abc")
+
+	e.Reveal(true)
+	assertString(t, "Revealed", e.displayText(), "abc")
+	assertString(t, "Revealed selection", e.GetSelectedText(), "")
+	e.SetFocused(false)
+	assertBool(t, "Un focus", "IsRevealed", e.IsRevealed(), false)
+
+	e.SetMasked(false)
+	e.SetFocused(true)
+	e.SetSelectedTextBounds(0, 1)
+	assertString(t, "Un masked selection", e.GetSelectedText(), "ab")
+}
+
+func typeString(e *SDL_Entry, s string) {
+	for _, c := range s {
+		e.KeyPress(int(c), false, true)
+	}
+}
+
+/*
+Do what Draw does before drawing, without a renderer or font. Every rune is 10 x 20 pixels
+so it is 10 pixels wide in an entry 24 high. Call it after the text changes where Draw would be called.
+*/
+func layoutEntry(e *SDL_Entry) {
+	res := GetResourceInstance()
+	fg := e.GetForeground()
+	res.cacheLock.Lock()
+	for _, c := range e.displayText() {
+		key := fmt.Sprintf("|%c%d", c, GetColourId(fg))
+		if !res.textureCache.Peek(key) {
+			res.textureCache.Add(key, &SDL_TextureCacheEntry{value: string(c), w: 10, h: 20})
+		}
+	}
+	res.cacheLock.Unlock()
+	e.screenDataLock.Lock()
+	defer e.screenDataLock.Unlock()
+	e.layoutNoLock(nil, nil)
+}
+
+func ctrlKey(e *SDL_Entry, c int) {
+	e.KeyPress(sdl.K_LCTRL, true, true)
+	e.KeyPress(c, true, true)
+	e.KeyPress(sdl.K_LCTRL, true, false)
+}
+
+func assertString(t *testing.T, message1 string, val, expected string) {
+	if val != expected {
+		t.Errorf("%s: Actual '%s' Expected '%s'", message1, val, expected)
+	}
+}
+
+func TestEntryMultiByteEditing(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	typeString(e, "héllo")
+	assertString(t, "Typed", e.GetText(), "héllo")
+	assertInt(t, "Length in runes", e.textLen, 5)
+	assertInt(t, "Cursor at end", e.cursor, 5)
+
+	e.MoveCursor(-3) // Between 'é' and 'l'
+	typeString(e, "ü")
+	assertString(t, "Insert after multi byte", e.GetText(), "héüllo")
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertString(t, "Backspace multi byte", e.GetText(), "hllo")
+	assertInt(t, "Cursor after backspace", e.cursor, 1)
+	e.KeyPress(sdl.K_DELETE, true, true)
+	assertString(t, "Delete", e.GetText(), "hlo")
+	typeString(e, "日本")
+	assertString(t, "Insert CJK", e.GetText(), "h日本lo")
+	e.SetCursor(99)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertString(t, "Backspace at end", e.GetText(), "h日本l")
+}
+
+func TestEntryMultiByteSelectionClipboardUndo(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "aé日b", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	err := e.SetSelectedTextBounds(1, 2)
+	if err != nil {
+		t.Errorf("Unexpected error %s", err.Error())
+	}
+	assertString(t, "Selected", e.GetSelectedText(), "é日")
+	mc := useMemoryClipboard(t)
+	ctrlKey(e, sdl.K_c)
+	s, _ := mc.GetText()
+	assertString(t, "Copied", s, "é日")
+
+	e.ClearSelection()
+	e.SetCursor(99)
+	ctrlKey(e, sdl.K_v)
+	assertString(t, "Pasted", e.GetText(), "aé日bé日")
+	assertInt(t, "Cursor after paste", e.cursor, 6)
+	typeString(e, "ö")
+	assertString(t, "Typed after paste", e.GetText(), "aé日bé日ö")
+
+	ctrlKey(e, sdl.K_z)
+	assertString(t, "Undo typing", e.GetText(), "aé日bé日")
+	ctrlKey(e, sdl.K_z)
+	assertString(t, "Undo paste", e.GetText(), "aé日b")
+
+	e.SetText("x/ü.y")
+	e.SetCursor(2)
+	e.selectAtCursor(3)
+	assertString(t, "Triple click", e.GetSelectedText(), "x/ü.y")
+}
+
+func TestEntryTextInputAndComposition(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "ab", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	assertBool(t, "Not focused", "TextInput", e.TextInput("x"), false)
+	e.SetFocused(true)
+	e.SetCursor(1)
+	e.TextEditing("ni", 2, 0)
+	assertString(t, "Composing", e.GetComposition(), "ni")
+	assertString(t, "Composition not in text", e.GetText(), "ab")
+	assertBool(t, "Backspace owned by IME", "KeyPress", e.KeyPress(sdl.K_BACKSPACE, true, true), true)
+	assertString(t, "Backspace ignored", e.GetText(), "ab")
+
+	e.TextInput("你好")
+	assertString(t, "Committed", e.GetText(), "a你好b")
+	assertString(t, "Composition cleared", e.GetComposition(), "")
+	assertInt(t, "Cursor after commit", e.cursor, 3)
+
+	e.TextEditing("x", 1, 0)
+	e.SetFocused(false)
+	assertString(t, "Composition cleared on un focus", e.GetComposition(), "")
+
+	// A masked entry does not show what is being composed
+	e.SetMasked(true)
+	e.SetFocused(true)
+	e.TextEditing("secret", 6, 0)
+	assertString(t, "Masked composition", e.displayComposition(), "••••••")
+	e.Reveal(true)
+	assertString(t, "Revealed composition", e.displayComposition(), "secret")
+	e.SetFocused(false)
+}
+
+func shiftKey(e *SDL_Entry, c int) {
+	e.KeyPress(sdl.K_LSHIFT, true, true)
+	e.KeyPress(c, true, true)
+	e.KeyPress(sdl.K_LSHIFT, true, false)
+}
+
+func TestEntryKeyboardSelection(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	typeString(e, "héllo wörld")
+	e.KeyPress(sdl.K_HOME, true, true)
+	assertInt(t, "Home", e.cursor, 0)
+	e.KeyPress(sdl.K_LSHIFT, true, true)
+	e.KeyPress(sdl.K_RIGHT, true, true)
+	e.KeyPress(sdl.K_RIGHT, true, true)
+	assertString(t, "Shift right", e.GetSelectedText(), "hé")
+	e.KeyPress(sdl.K_LEFT, true, true)
+	assertString(t, "Shift left", e.GetSelectedText(), "h")
+	e.KeyPress(sdl.K_LEFT, true, true)
+	assertString(t, "Shift left to anchor", e.GetSelectedText(), "")
+	e.KeyPress(sdl.K_END, true, true)
+	assertString(t, "Shift end", e.GetSelectedText(), "héllo wörld")
+	e.KeyPress(sdl.K_LSHIFT, true, false)
+	e.KeyPress(sdl.K_LEFT, true, true)
+	assertString(t, "Cleared", e.GetSelectedText(), "")
+	assertInt(t, "Left", e.cursor, 10)
+
+	e.KeyPress(sdl.K_LCTRL, true, true)
+	e.KeyPress(sdl.K_LEFT, true, true)
+	assertInt(t, "Word left", e.cursor, 6)
+	e.KeyPress(sdl.K_LEFT, true, true)
+	assertInt(t, "Word left again", e.cursor, 0)
+	e.KeyPress(sdl.K_RIGHT, true, true)
+	assertInt(t, "Word right", e.cursor, 6)
+	e.KeyPress(sdl.K_LCTRL, true, false)
+
+	shiftKey(e, sdl.K_HOME)
+	assertString(t, "Shift home", e.GetSelectedText(), "héllo ")
+	ctrlKey(e, sdl.K_a)
+	assertString(t, "Select all", e.GetSelectedText(), "héllo wörld")
+	assertInt(t, "Select all cursor", e.cursor, 11)
+}
+
+func TestEntryKeyboardEditing(t *testing.T) {
+	events := []ENTRY_EVENT_TYPE{}
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, func(old, new string, ev ENTRY_EVENT_TYPE) (string, error) {
+		if ev != ENTRY_EVENT_FOCUS && ev != ENTRY_EVENT_UN_FOCUS {
+			events = append(events, ev)
+		}
+		return new, nil
+	})
+	e.SetFocused(true)
+	typeString(e, "one two three")
+	events = events[:0]
+
+	e.KeyPress(sdl.K_LCTRL, true, true)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertString(t, "Ctrl+Backspace", e.GetText(), "one two ")
+	assertInt(t, "Ctrl+Backspace cursor", e.cursor, 8)
+	e.KeyPress(sdl.K_HOME, true, true)
+	e.KeyPress(sdl.K_DELETE, true, true)
+	assertString(t, "Ctrl+Delete", e.GetText(), "two ")
+	assertInt(t, "Ctrl+Delete cursor", e.cursor, 0)
+	e.KeyPress(sdl.K_LCTRL, true, false)
+
+	shiftKey(e, sdl.K_RIGHT)
+	shiftKey(e, sdl.K_RIGHT)
+	mc := useMemoryClipboard(t)
+	ctrlKey(e, sdl.K_x)
+	assertString(t, "Ctrl+X", e.GetText(), "o ")
+	s, _ := mc.GetText()
+	assertString(t, "Ctrl+X clipboard", s, "tw")
+	assertInt(t, "Ctrl+X cursor", e.cursor, 0)
+	assertString(t, "Ctrl+X clears selection", e.GetSelectedText(), "")
+	ctrlKey(e, sdl.K_z)
+	assertString(t, "Ctrl+X undo", e.GetText(), "two ")
+
+	assertInt(t, "Events", len(events), 4)
+	assertInt(t, "Event BS", int(events[0]), int(ENTRY_EVENT_BS))
+	assertInt(t, "Event DELETE", int(events[1]), int(ENTRY_EVENT_DELETE))
+	assertInt(t, "Event cut", int(events[2]), int(ENTRY_EVENT_DELETE))
+}
+
+func TestEntryUndoRedo(t *testing.T) {
+	events := []ENTRY_EVENT_TYPE{}
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, func(old, new string, ev ENTRY_EVENT_TYPE) (string, error) {
+		if ev != ENTRY_EVENT_FOCUS && ev != ENTRY_EVENT_UN_FOCUS {
+			events = append(events, ev)
+		}
+		return new, nil
+	})
+	e.SetFocused(true)
+	typeString(e, "hello")
+	e.KeyPress(sdl.K_LEFT, true, true)
+	typeString(e, "XY")
+	assertString(t, "Typed", e.GetText(), "hellXYo")
+	assertInt(t, "Typing merged", len(e.history), 2)
+
+	e.SetSelectedTextBounds(1, 2)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertString(t, "Backspace", e.GetText(), "hlXYo")
+
+	events = events[:0]
+	ctrlKey(e, sdl.K_z)
+	assertString(t, "Undo backspace", e.GetText(), "hellXYo")
+	assertInt(t, "Undo cursor", e.cursor, 6)
+	assertString(t, "Undo selection", e.GetSelectedText(), "el")
+	ctrlKey(e, sdl.K_z)
+	assertString(t, "Undo typing", e.GetText(), "hello")
+	assertInt(t, "Undo typing cursor", e.cursor, 4)
+	ctrlKey(e, sdl.K_z)
+	assertString(t, "Undo first typing", e.GetText(), "")
+	assertBool(t, "Undo", "CanUndo", e.CanUndo(), false)
+	assertBool(t, "Undo empty", "Undo", e.Undo(), false)
+
+	ctrlKey(e, sdl.K_y)
+	assertString(t, "Redo", e.GetText(), "hello")
+	e.KeyPress(sdl.K_LSHIFT, true, true)
+	ctrlKey(e, sdl.K_z)
+	e.KeyPress(sdl.K_LSHIFT, true, false)
+	assertString(t, "Ctrl+Shift+Z", e.GetText(), "hellXYo")
+	assertInt(t, "Events", len(events), 5)
+	assertInt(t, "Event undo", int(events[0]), int(ENTRY_EVENT_UNDO))
+	assertInt(t, "Event redo", int(events[4]), int(ENTRY_EVENT_REDO))
+
+	typeString(e, "!")
+	assertBool(t, "Edit clears redo", "CanRedo", e.CanRedo(), false)
+
+	e.SetUndoMax(1)
+	assertInt(t, "Bounded", len(e.history), 1)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertInt(t, "Still bounded", len(e.history), 1)
+	e.SetUndoMax(-1)
+	assertInt(t, "Negative max", e.GetUndoMax(), 0)
+	assertInt(t, "Negative max", len(e.history), 0)
+}
+
+func TestEntryReplaceSelectionAndOverwrite(t *testing.T) {
+	calls := 0
+	var lastOld, lastNew string
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, func(old, new string, ev ENTRY_EVENT_TYPE) (string, error) {
+		if ev != ENTRY_EVENT_FOCUS && ev != ENTRY_EVENT_UN_FOCUS {
+			calls++
+			lastOld = old
+			lastNew = new
+		}
+		return new, nil
+	})
+	e.SetFocused(true)
+	typeString(e, "abcdef")
+	e.SetSelectedTextBounds(1, 3)
+	calls = 0
+	typeString(e, "X")
+	assertString(t, "Typed over selection", e.GetText(), "aXef")
+	assertInt(t, "Typed cursor", e.cursor, 2)
+	assertInt(t, "One onChange", calls, 1)
+	assertString(t, "onChange old", lastOld, "abcdef")
+	assertString(t, "onChange new", lastNew, "aXef")
+
+	mc := useMemoryClipboard(t)
+	mc.SetText("日本")
+	e.SetSelectedTextBounds(0, 1)
+	ctrlKey(e, sdl.K_v)
+	assertString(t, "Paste over selection", e.GetText(), "日本ef")
+	assertInt(t, "Paste cursor", e.cursor, 2)
+
+	e.SetSelectedTextBounds(1, 2)
+	e.KeyPress(sdl.K_DELETE, true, true)
+	assertString(t, "Delete selection", e.GetText(), "日f")
+	assertInt(t, "Delete cursor", e.cursor, 1)
+	e.SetSelectedTextBounds(0, 1)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertString(t, "Backspace selection", e.GetText(), "")
+	assertInt(t, "onChange calls", calls, 4)
+
+	// Ctrl+Backspace and Ctrl+Delete delete the selection, not a word
+	typeString(e, "one two three")
+	e.SetSelectedTextBounds(5, 6)
+	e.KeyPress(sdl.K_LCTRL, true, true)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertString(t, "Ctrl+Backspace selection", e.GetText(), "one t three")
+	assertInt(t, "Ctrl+Backspace selection cursor", e.cursor, 5)
+	e.SetSelectedTextBounds(0, 1)
+	e.KeyPress(sdl.K_DELETE, true, true)
+	e.KeyPress(sdl.K_LCTRL, true, false)
+	assertString(t, "Ctrl+Delete selection", e.GetText(), "e t three")
+	assertInt(t, "Ctrl+Delete selection cursor", e.cursor, 0)
+	e.SetText("")
+
+	typeString(e, "hello")
+	e.KeyPress(sdl.K_HOME, true, true)
+	e.KeyPress(sdl.K_INSERT, true, true)
+	assertBool(t, "Insert key", "IsOverwrite", e.IsOverwrite(), true)
+	typeString(e, "HE")
+	assertString(t, "Overwrite", e.GetText(), "HEllo")
+	e.KeyPress(sdl.K_END, true, true)
+	typeString(e, "!")
+	assertString(t, "Overwrite at end", e.GetText(), "HEllo!")
+	e.KeyPress(sdl.K_INSERT, true, true)
+	e.KeyPress(sdl.K_HOME, true, true)
+	typeString(e, "_")
+	assertString(t, "Insert", e.GetText(), "_HEllo!")
+}
+
+func TestEntryMaxLength(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	e.SetMaxLength(5)
+	typeString(e, "日本語abc")
+	assertString(t, "Typing stops", e.GetText(), "日本語ab")
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	mc := useMemoryClipboard(t)
+	mc.SetText("xyz")
+	ctrlKey(e, sdl.K_v)
+	assertString(t, "Paste cut", e.GetText(), "日本語xy")
+	assertInt(t, "Paste cursor", e.cursor, 5)
+	e.SetSelectedTextBounds(0, 1)
+	ctrlKey(e, sdl.K_v)
+	assertString(t, "Paste over selection", e.GetText(), "xy語xy")
+	e.KeyPress(sdl.K_HOME, true, true)
+	e.SetOverwrite(true)
+	typeString(e, "12")
+	assertString(t, "Overwrite", e.GetText(), "12語xy")
+	e.KeyPress(sdl.K_END, true, true)
+	typeString(e, "3")
+	assertString(t, "Overwrite at end", e.GetText(), "12語xy")
+	e.SetOverwrite(false)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	layoutEntry(e)
+	e.Drop(NewTextPayload("abc"), 190, 10)
+	assertString(t, "Drop cut", e.GetText(), "12語ab")
+	e.Drop(NewFilePayload("/tmp/x.txt"), 0, 0)
+	assertString(t, "File drop cut", e.GetText(), "/tmp/")
+}
+
+func TestEntryReadOnly(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "read only", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetReadOnly(true)
+	e.SetFocused(true)
+	assertBool(t, "Focus", "IsFocused", e.IsFocused(), true)
+	typeString(e, "x")
+	assertBool(t, "TextInput", "TextInput", e.TextInput("x"), false)
+	assertBool(t, "TextEditing", "TextEditing", e.TextEditing("x", 0, 1), false)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertString(t, "Not changed", e.GetText(), "read only")
+
+	e.KeyPress(sdl.K_HOME, true, true)
+	shiftKey(e, sdl.K_RIGHT)
+	shiftKey(e, sdl.K_RIGHT)
+	assertString(t, "Select", e.GetSelectedText(), "re")
+	mc := useMemoryClipboard(t)
+	ctrlKey(e, sdl.K_c)
+	s, _ := mc.GetText()
+	assertString(t, "Copy", s, "re")
+	ctrlKey(e, sdl.K_x)
+	assertString(t, "No cut", e.GetText(), "read only")
+	ctrlKey(e, sdl.K_v)
+	assertString(t, "No paste", e.GetText(), "read only")
+
+	disabled := GetResourceInstance().GetColour(WIDGET_COLOUR_INDEX_DISABLE, WIDGET_COLOUR_STYLE_BG)
+	focus := GetResourceInstance().GetColour(WIDGET_COLOUR_INDEX_FOCUS, WIDGET_COLOUR_STYLE_FG)
+	assertBool(t, "Read only bg", "GetBackground", *e.GetBackground() == *disabled, true)
+	assertBool(t, "Read only fg", "GetForeground", *e.GetForeground() == *focus, true)
+
+	res := GetResourceInstance()
+	assertBool(t, "Read only", "text input", res.textInputOwner == e, false)
+	e.SetReadOnly(false)
+	assertBool(t, "Editable", "text input", res.textInputOwner == e, true)
+	e.KeyPress(sdl.K_END, true, true)
+	typeString(e, "!")
+	assertString(t, "Editable", e.GetText(), "read only!")
+	e.SetReadOnly(true)
+	assertBool(t, "Read only while focused", "text input", res.textInputOwner == e, false)
+	e.SetReadOnly(false)
+	e.SetPlaceholderText("Name")
+	assertString(t, "Placeholder", e.GetPlaceholderText(), "Name")
+}
+
+func TestEntryPixelScrolling(t *testing.T) {
+	e := NewSDLEntry(0, 0, 100, 24, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	typeString(e, "abcdefghijklmnopqrstuvwxyz0123")
+	layoutEntry(e)
+	// 30 runes 10 pixels wide. Text area is 10 to 95
+	assertInt(t, "Text width", int(e.textWidthNoLock()), 300)
+	assertInt(t, "Scrolled to end", int(e.scrollX), 300-85)
+
+	e.Click(&SDL_MouseData{x: 15, y: 5})
+	assertInt(t, "Click when scrolled", e.cursor, 22)
+	e.Click(&SDL_MouseData{x: 21, y: 5})
+	assertInt(t, "Click nearest edge", e.cursor, 23)
+	layoutEntry(e)
+	assertInt(t, "Cursor visible, no scroll", int(e.scrollX), 215)
+
+	e.KeyPress(sdl.K_HOME, true, true)
+	layoutEntry(e)
+	assertInt(t, "Home", int(e.scrollX), 0)
+	for i := 0; i < 12; i++ {
+		e.KeyPress(sdl.K_RIGHT, true, true)
+	}
+	layoutEntry(e)
+	assertInt(t, "Right keeps cursor in view", int(e.scrollX), 120-85)
+
+	e.KeyPress(sdl.K_HOME, true, true)
+	layoutEntry(e)
+	e.Click(&SDL_MouseData{x: 26, y: 5, dragging: true, draggingX: 56})
+	assertString(t, "Drag select", e.GetSelectedText(), "cde")
+	e.Click(&SDL_MouseData{x: 26, y: 5, dragging: true, draggingX: 150})
+	for i := 0; i < 20; i++ {
+		layoutEntry(e)
+	}
+	assertInt(t, "Auto scroll right", int(e.scrollX), 215)
+	assertString(t, "Drag past right", e.GetSelectedText(), "cdefghijklmnopqrstuvwxyz0123")
+	e.Click(&SDL_MouseData{x: 26, y: 5, dragging: true, draggingX: 0})
+	for i := 0; i < 80; i++ {
+		layoutEntry(e)
+	}
+	assertInt(t, "Auto scroll left", int(e.scrollX), 0)
+	assertString(t, "Drag past left", e.GetSelectedText(), "ab")
+	e.Click(&SDL_MouseData{x: 26, y: 5, dragged: true})
+	assertBool(t, "Drag done", "dragging", e.dragging, false)
+	assertString(t, "Selection kept", e.GetSelectedText(), "ab")
+}
+
+func TestEntryScrollNumber(t *testing.T) {
+	var events []ENTRY_EVENT_TYPE
+	e := NewSDLEntry(0, 0, 100, 24, 1, "5", WIDGET_STYLE_DRAW_BORDER_AND_BG, func(old, new string, ev ENTRY_EVENT_TYPE) (string, error) {
+		events = append(events, ev)
+		return new, nil
+	})
+	assertBool(t, "No step", "consumed", e.Scroll(0, 1, 1), false)
+	assertString(t, "No step", e.GetText(), "5")
+
+	e.SetScrollStep(1)
+	assertBool(t, "Up", "consumed", e.Scroll(0, 1, 1), true)
+	assertString(t, "Up", e.GetText(), "6")
+	e.Scroll(0, -3, -3)
+	assertString(t, "Down 3", e.GetText(), "3")
+	assertInt(t, "Event", int(events[0]), int(ENTRY_EVENT_SCROLL))
+	e.Undo()
+	assertString(t, "Undo", e.GetText(), "6")
+
+	e.SetScrollStep(0.1)
+	e.SetText("0.2")
+	e.Scroll(0, 1, 1)
+	assertString(t, "Decimal places from step", e.GetText(), "0.3")
+	e.SetText("")
+	e.Scroll(0, -1, -1)
+	assertString(t, "Empty is 0", e.GetText(), "-0.1")
+	e.SetText("abc")
+	assertBool(t, "Not a number", "consumed", e.Scroll(0, 1, 1), false)
+	assertString(t, "Not a number", e.GetText(), "abc")
+	e.SetText("1")
+	e.SetReadOnly(true)
+	e.Scroll(0, 1, 1)
+	assertString(t, "Read only", e.GetText(), "1")
+}
@@ -27,8 +27,13 @@ type SDL_FileList struct {
 	onSelect     func(string, FILE_LIST_RESPONSE_CODE, int32) bool
 	ret          FILE_LIST_RESPONSE_CODE
 	rowHeight    int32
+	scrollRows   int // Rows scrolled off the top of the list
 }
 
+var _ SDL_Scrollable = (*SDL_FileList)(nil) // Ensure SDL_FileList 'is a' SDL_Scrollable
+
+const fileList_HEADER_COUNT = 2 // The Cancel button and path label. The rows follow them
+
 func NewFileList(x, y, rh, id int32, currentPath string, font *ttf.Font, style STATE_BITS, onSelect func(string, FILE_LIST_RESPONSE_CODE, int32) bool, filter func(bool, string) bool) (*SDL_FileList, error) {
 	stat, err := os.Stat(currentPath)
 	if err != nil {
@@ -61,6 +66,7 @@ func (fl *SDL_FileList) Reload(currentPath string) {
 	if err == nil {
 		fl.currentPath = currentPath
 		fl.swapTemp()
+		fl.scrollRows = 0
 	}
 }
 
@@ -108,6 +114,7 @@ func (fl *SDL_FileList) populateTemp(currentPath string) error {
 			y = y + h
 			wid++
 			lab = NewSDLLabel(x, y, w, h, wid, fmt.Sprintf("D:%s", fil.Name()), ALIGN_LEFT, WIDGET_STYLE_DRAW_BG)
+			lab.SetDragSource(fileListDragSource(filepath.Join(abs_fname, fil.Name())))
 			lab.SetOnClick(func(s string, id, mouseX, mouseY int32) bool {
 				fil := filepath.Join(fl.currentPath, s[2:])
 				fl.onSelect(fil, FILE_LIST_PATH_SELECT, id)
@@ -122,6 +129,7 @@ func (fl *SDL_FileList) populateTemp(currentPath string) error {
 			y = y + h
 			wid++
 			lab = NewSDLLabel(x, y, w, h, wid, fmt.Sprintf("F:%s", fil.Name()), ALIGN_LEFT, WIDGET_STYLE_DRAW_BG)
+			lab.SetDragSource(fileListDragSource(filepath.Join(abs_fname, fil.Name())))
 			lab.SetOnClick(func(s string, id, mouseX, mouseY int32) bool {
 				fil := filepath.Clean(filepath.Join(fl.currentPath, s[2:]))
 				cur, err := os.Getwd()
@@ -150,6 +158,52 @@ func (fl *SDL_FileList) populateTemp(currentPath string) error {
 	return nil
 }
 
+/*
+File and directory rows can be dragged. The payload is the absolute path
+*/
+func fileListDragSource(path string) func(int32, int32) *SDL_DragPayload {
+	return func(x, y int32) *SDL_DragPayload {
+		return NewFilePayload(path)
+	}
+}
+
+/*
+The mouse wheel scrolls the rows under the header. Rows scrolled off the top are hidden.
+The last row can be scrolled up to the top.
+*/
+func (fl *SDL_FileList) Scroll(dx, dy int32, precise float32) bool {
+	if !fl.IsVisible() || dy == 0 {
+		return false
+	}
+	list := fl.ListWidgets()
+	if len(list) <= fileList_HEADER_COUNT {
+		return false
+	}
+	rows := list[fileList_HEADER_COUNT:]
+	s := fl.scrollRows - int(dy)
+	if s > len(rows)-1 {
+		s = len(rows) - 1
+	}
+	if s < 0 {
+		s = 0
+	}
+	if s == fl.scrollRows {
+		return false
+	}
+	// The first visible row is at the top. There is no 'D:..' row above the first directory in a root directory
+	_, top := rows[fl.scrollRows].GetPosition()
+	fl.scrollRows = s
+	for i, row := range rows {
+		row.SetVisible(i >= s)
+		row.SetPosition(fl.x, top+(fl.rowHeight*int32(i-s)))
+	}
+	return true
+}
+
+func (fl *SDL_FileList) GetScrollRows() int {
+	return fl.scrollRows
+}
+
 func (fl *SDL_FileList) Show(viewPort sdl.Rect) {
 	fl.SDL_WidgetSubGroup.SetVisible(true)
 }
@@ -0,0 +1,69 @@
+package go_sdl_widget
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestFileListScroll(t *testing.T) {
+	dir := t.TempDir()
+	for _, n := range []string{"a.txt", "b.txt", "c.txt"} {
+		os.WriteFile(filepath.Join(dir, n), []byte(n), 0644)
+	}
+	fl, err := NewFileList(0, 0, 20, 10, dir, nil, WIDGET_STYLE_DRAW_BG, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fl.Reload(dir)
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 1000, 200, 1, WIDGET_STYLE_DRAW_NONE)
+	sg.Add(fl)
+	// Rows: D:.. F:a.txt F:b.txt F:c.txt
+	rows := fl.ListWidgets()[fileList_HEADER_COUNT:]
+	assertInt(t, "Rows", len(rows), 4)
+	wheel := func(y int32) bool {
+		return wg.HandleEvent(&sdl.MouseWheelEvent{Type: sdl.MOUSEWHEEL, Y: y})
+	}
+	wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 10, Y: 50})
+	assertBool(t, "Wheel up at top", "consumed", wheel(1), false)
+	assertBool(t, "Wheel down from row", "consumed", wheel(-1), true)
+	assertInt(t, "Scrolled", fl.GetScrollRows(), 1)
+	assertBool(t, "First row hidden", "visible", rows[0].IsVisible(), false)
+	_, y := rows[1].GetPosition()
+	assertInt(t, "Second row moved up", int(y), 20)
+	wheel(-10)
+	assertInt(t, "Last row at the top", fl.GetScrollRows(), 3)
+	_, y = rows[3].GetPosition()
+	assertInt(t, "Last row at the top", int(y), 20)
+	wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 10, Y: 25})
+	wheel(2)
+	assertInt(t, "Scrolled back", fl.GetScrollRows(), 1)
+	assertBool(t, "Row shown again", "visible", rows[1].IsVisible(), true)
+	fl.Reload(dir)
+	assertInt(t, "Reload", fl.GetScrollRows(), 0)
+}
+
+func TestFileListScrollRootDirectory(t *testing.T) {
+	root := filepath.VolumeName(os.TempDir()) + string(filepath.Separator)
+	fl, err := NewFileList(0, 0, 20, 10, root, nil, WIDGET_STYLE_DRAW_BG, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fl.Reload(root)
+	// No D:.. row so the first directory is a row lower than in TestFileListScroll
+	rows := fl.ListWidgets()[fileList_HEADER_COUNT:]
+	if len(rows) < 2 {
+		t.Skip("root directory has less than two rows")
+	}
+	_, top := rows[0].GetPosition()
+	assertInt(t, "First row", int(top), 40)
+	assertBool(t, "Scroll down", "consumed", fl.Scroll(0, -1, -1), true)
+	_, y := rows[1].GetPosition()
+	assertInt(t, "Second row moved up", int(y), int(top))
+	fl.Scroll(0, 1, 1)
+	_, y = rows[0].GetPosition()
+	assertInt(t, "First row back", int(y), int(top))
+}
@@ -0,0 +1,435 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/veandco/go-sdl2/gfx"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+const (
+	DIAL_DEFAULT_START_ANGLE float64 = 135 // Degrees clockwise from 3 o'clock. Bottom left.
+	DIAL_DEFAULT_SWEEP_ANGLE float64 = 270 // Degrees clockwise from the start angle. Bottom right.
+	DIAL_DEFAULT_DRAG_PIXELS int32   = 200 // Vertical drag distance to turn a knob from min to max.
+)
+
+/****************************************************************************************
+* SDL_GaugeRange code
+* A coloured band drawn around the edge of a gauge. From and To are values NOT angles
+**/
+type SDL_GaugeRange struct {
+	From, To float64
+	Colour   *sdl.Color
+}
+
+/****************************************************************************************
+* sdl_DialValue code
+* Value, range and angle state shared by SDL_Gauge and SDL_Knob.
+**/
+type sdl_DialValue struct {
+	min, max, value   float64
+	startAngle, sweep float64
+	format            string
+	cacheKey          string
+}
+
+func newDialValue(min, max, value float64, key string) sdl_DialValue {
+	dv := sdl_DialValue{min: min, max: max, startAngle: DIAL_DEFAULT_START_ANGLE, sweep: DIAL_DEFAULT_SWEEP_ANGLE, format: "%.0f", cacheKey: key}
+	if dv.max < dv.min {
+		dv.min, dv.max = max, min
+	}
+	dv.SetValue(value)
+	return dv
+}
+
+func (dv *sdl_DialValue) GetValue() float64 {
+	return dv.value
+}
+
+/*
+Set the value. It is clamped to the min and max values.
+Returns true if the value changed.
+*/
+func (dv *sdl_DialValue) SetValue(v float64) bool {
+	v = dv.clamp(v)
+	if v != dv.value {
+		dv.value = v
+		return true
+	}
+	return false
+}
+
+func (dv *sdl_DialValue) GetRange() (float64, float64) {
+	return dv.min, dv.max
+}
+
+func (dv *sdl_DialValue) SetRange(min, max float64) {
+	if max < min {
+		min, max = max, min
+	}
+	dv.min = min
+	dv.max = max
+	dv.value = dv.clamp(dv.value)
+}
+
+/*
+Set the angle (degrees clockwise from 3 o'clock) of the min value and the angle swept to the max value.
+*/
+func (dv *sdl_DialValue) SetAngles(startAngle, sweep float64) {
+	dv.startAngle = startAngle
+	dv.sweep = sweep
+}
+
+func (dv *sdl_DialValue) GetAngles() (float64, float64) {
+	return dv.startAngle, dv.sweep
+}
+
+/*
+Set the fmt format used to display the value. An empty format will not display the value.
+*/
+func (dv *sdl_DialValue) SetFormat(format string) {
+	dv.format = format
+}
+
+func (dv *sdl_DialValue) GetFormat() string {
+	return dv.format
+}
+
+func (dv *sdl_DialValue) clamp(v float64) float64 {
+	if v < dv.min {
+		return dv.min
+	}
+	if v > dv.max {
+		return dv.max
+	}
+	return v
+}
+
+func (dv *sdl_DialValue) valueToAngle(v float64) float64 {
+	span := dv.max - dv.min
+	if span == 0 {
+		return dv.startAngle
+	}
+	return dv.startAngle + (dv.sweep * ((dv.clamp(v) - dv.min) / span))
+}
+
+func (dv *sdl_DialValue) drawValueText(renderer *sdl.Renderer, font *ttf.Font, colour *sdl.Color, cx, y, th int32) {
+	if dv.format == "" || font == nil || th <= 0 {
+		return
+	}
+	text := fmt.Sprintf(dv.format, dv.value)
+	ctwe, err := GetResourceInstance().UpdateTextureFromString(renderer, dv.cacheKey, text, font, colour)
+	if err != nil || ctwe.h == 0 {
+		return
+	}
+	tw := int32(float32(ctwe.w) * (float32(th) / float32(ctwe.h)))
+	renderer.Copy(ctwe.texture, nil, &sdl.Rect{X: cx - (tw / 2), Y: y, W: tw, H: th})
+}
+
+/****************************************************************************************
+* SDL_Gauge code
+* Implements SDL_Widget cos it is one!
+* A read only circular gauge with a needle, coloured ranges and the value as text
+**/
+type SDL_Gauge struct {
+	SDL_WidgetBase
+	sdl_DialValue
+	ranges []*SDL_GaugeRange
+	ticks  int
+}
+
+var _ SDL_Widget = (*SDL_Gauge)(nil) // Ensure SDL_Gauge 'is a' SDL_Widget
+
+func NewSDLGauge(x, y, w, h, id int32, min, max, value float64, style STATE_BITS) *SDL_Gauge {
+	g := &SDL_Gauge{ranges: make([]*SDL_GaugeRange, 0), ticks: 10}
+	g.sdl_DialValue = newDialValue(min, max, value, fmt.Sprintf("gauge:%d:%d", id, rand.Intn(100)))
+	g.SDL_WidgetBase = initBase(x, y, w, h, id, g, 0, false, style, nil)
+	return g
+}
+
+func (g *SDL_Gauge) AddRange(from, to float64, colour *sdl.Color) *SDL_GaugeRange {
+	if to < from {
+		from, to = to, from
+	}
+	r := &SDL_GaugeRange{From: from, To: to, Colour: colour}
+	g.ranges = append(g.ranges, r)
+	return r
+}
+
+func (g *SDL_Gauge) ClearRanges() {
+	g.ranges = make([]*SDL_GaugeRange, 0)
+}
+
+/*
+Set the number of divisions marked around the edge of the gauge. 0 for no tick marks.
+*/
+func (g *SDL_Gauge) SetTicks(ticks int) {
+	if ticks < 0 {
+		ticks = 0
+	}
+	g.ticks = ticks
+}
+
+func (g *SDL_Gauge) GetTicks() int {
+	return g.ticks
+}
+
+func (g *SDL_Gauge) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
+	if g.IsVisible() {
+		cx, cy, rad := dialCentre(g.x, g.y, g.w, g.h)
+		if rad <= 0 {
+			return nil
+		}
+		band := ifLessUseN(rad/8, 2)
+		if g.ShouldDrawBackground() {
+			gfx.FilledCircleColor(renderer, cx, cy, rad, *g.GetBackground())
+		}
+		for _, r := range g.ranges {
+			dialDrawArc(renderer, cx, cy, rad-2, band, g.valueToAngle(r.From), g.valueToAngle(r.To), r.Colour)
+		}
+		bc := g.GetBorderColour()
+		if g.ticks > 0 {
+			step := g.sweep / float64(g.ticks)
+			for i := 0; i <= g.ticks; i++ {
+				dialDrawSpoke(renderer, cx, cy, rad-band-2, rad-2, 1, g.startAngle+(step*float64(i)), bc)
+			}
+		}
+		fg := g.GetForeground()
+		g.drawValueText(renderer, font, fg, cx, cy+(rad/3), rad/3)
+		dialDrawNeedle(renderer, cx, cy, rad-band-4, ifLessUseN(rad/16, 2), g.valueToAngle(g.value), fg)
+		if g.ShouldDrawBorder() {
+			gfx.CircleColor(renderer, cx, cy, rad, *bc)
+			gfx.CircleColor(renderer, cx, cy, rad-1, *bc)
+		}
+	}
+	return nil
+}
+
+/****************************************************************************************
+* SDL_Knob code
+* Implements SDL_Widget cos it is one!
+* An interactive rotary control. Turned by dragging the mouse up or down or by the mouse wheel
+**/
+type SDL_Knob struct {
+	SDL_WidgetBase
+	sdl_DialValue
+	step       float64
+	dragPixels int32
+	dragStartY int32
+	dragValue  float64
+	dragging   bool
+	onChange   func(float64, float64) (float64, error)
+}
+
+var _ SDL_Widget = (*SDL_Knob)(nil)     // Ensure SDL_Knob 'is a' SDL_Widget
+var _ SDL_Scrollable = (*SDL_Knob)(nil) // Ensure SDL_Knob 'is a' SDL_Scrollable
+
+func NewSDLKnob(x, y, w, h, id int32, min, max, value float64, style STATE_BITS, onChange func(float64, float64) (float64, error)) *SDL_Knob {
+	k := &SDL_Knob{dragPixels: DIAL_DEFAULT_DRAG_PIXELS, dragging: false, onChange: onChange}
+	k.sdl_DialValue = newDialValue(min, max, value, fmt.Sprintf("knob:%d:%d", id, rand.Intn(100)))
+	k.step = (k.max - k.min) / 100
+	k.SDL_WidgetBase = initBase(x, y, w, h, id, k, 0, true, style, nil)
+	return k
+}
+
+/*
+Set the amount the value changes for each click of the mouse wheel
+*/
+func (k *SDL_Knob) SetStep(step float64) {
+	k.step = step
+}
+
+func (k *SDL_Knob) GetStep() float64 {
+	return k.step
+}
+
+/*
+Set the vertical distance the mouse must be dragged to turn the knob from min to max
+*/
+func (k *SDL_Knob) SetDragPixels(p int32) {
+	if p > 0 {
+		k.dragPixels = p
+	}
+}
+
+func (k *SDL_Knob) SetOnChange(f func(float64, float64) (float64, error)) {
+	k.onChange = f
+}
+
+/*
+Turn the knob by the mouse wheel. Pass the Y value from the sdl.MouseWheelEvent.
+*/
+func (k *SDL_Knob) Wheel(y int32) bool {
+	if k.IsEnabled() && y != 0 {
+		return k.changeValue(k.value + (float64(y) * k.step))
+	}
+	return false
+}
+
+func (k *SDL_Knob) Scroll(dx, dy int32, precise float32) bool {
+	return k.Wheel(dy)
+}
+
+func (k *SDL_Knob) Click(md *SDL_MouseData) bool {
+	if k.IsEnabled() {
+		if md.IsDragging() {
+			if !k.dragging {
+				k.dragging = true
+				k.dragStartY = md.GetDraggingY()
+				k.dragValue = k.value
+				return true
+			}
+			dy := k.dragStartY - md.GetDraggingY() // Up is positive
+			k.changeValue(k.dragValue + ((k.max - k.min) * (float64(dy) / float64(k.dragPixels))))
+			return true
+		}
+		if k.dragging {
+			k.dragging = false
+			return true
+		}
+		return k.SDL_WidgetBase.Click(md)
+	}
+	return false
+}
+
+/*
+When focused the arrow keys turn the knob one step. Up and Right increase the value
+*/
+func (k *SDL_Knob) KeyPress(c int, ctrl, down bool) bool {
+	if down && ctrl && k.IsFocused() {
+		switch c | 0x40000000 {
+		case sdl.K_UP, sdl.K_RIGHT:
+			k.Wheel(1)
+			return true
+		case sdl.K_DOWN, sdl.K_LEFT:
+			k.Wheel(-1)
+			return true
+		}
+	}
+	return k.SDL_WidgetBase.KeyPress(c, ctrl, down)
+}
+
+func (k *SDL_Knob) changeValue(v float64) bool {
+	oldValue := k.value
+	newValue := k.clamp(v)
+	if newValue == oldValue {
+		return false
+	}
+	if k.onChange != nil {
+		var err error
+		newValue, err = k.onChange(oldValue, newValue)
+		k.SetError(err != nil)
+	}
+	return k.SetValue(newValue)
+}
+
+func (k *SDL_Knob) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
+	if k.IsVisible() {
+		cx, cy, rad := dialCentre(k.x, k.y, k.w, k.h)
+		if rad <= 0 {
+			return nil
+		}
+		band := ifLessUseN(rad/6, 2)
+		if k.ShouldDrawBackground() {
+			gfx.FilledCircleColor(renderer, cx, cy, rad, *k.GetBackground())
+		}
+		bc := k.GetBorderColour()
+		fg := k.GetForeground()
+		angle := k.valueToAngle(k.value)
+		dialDrawArc(renderer, cx, cy, rad-2, band, k.startAngle, k.startAngle+k.sweep, bc)
+		dialDrawArc(renderer, cx, cy, rad-2, band, k.startAngle, angle, fg)
+		dialDrawSpoke(renderer, cx, cy, rad/4, rad-band-4, ifLessUseN(rad/10, 2), angle, fg)
+		k.drawValueText(renderer, font, fg, cx, cy+(rad/3), rad/3)
+		if k.ShouldDrawBorder() {
+			gfx.CircleColor(renderer, cx, cy, rad, *bc)
+		}
+		k.drawFocusRing(renderer)
+	}
+	return nil
+}
+
+/****************************************************************************************
+* Dial drawing utilities
+**/
+
+/*
+Return the centre and radius of the largest circle that fits in the widget
+*/
+func dialCentre(x, y, w, h int32) (int32, int32, int32) {
+	rad := w / 2
+	if h < w {
+		rad = h / 2
+	}
+	return x + (w / 2), y + (h / 2), rad - 1
+}
+
+/*
+Draw an arc 'thick' pixels deep inside radius 'rad'. Angles are degrees clockwise from 3 o'clock.
+*/
+func dialDrawArc(renderer *sdl.Renderer, cx, cy, rad, thick int32, from, to float64, colour *sdl.Color) {
+	if colour == nil || to <= from {
+		return
+	}
+	if to-from >= 360 {
+		for i := int32(0); i < thick; i++ {
+			gfx.CircleColor(renderer, cx, cy, rad-i, *colour)
+		}
+		return
+	}
+	f := int32(math.Round(from))
+	t := int32(math.Round(to))
+	if f == t {
+		return
+	}
+	for i := int32(0); i < thick; i++ {
+		gfx.ArcColor(renderer, cx, cy, rad-i, f, t, *colour)
+	}
+}
+
+/*
+Draw a line along a radius from 'inner' to 'outer' at angle degrees clockwise from 3 o'clock.
+*/
+func dialDrawSpoke(renderer *sdl.Renderer, cx, cy, inner, outer, width int32, angle float64, colour *sdl.Color) {
+	rad := angle * DEG_TO_RAD
+	sinA := math.Sin(rad)
+	cosA := math.Cos(rad)
+	x1, y1 := rotatePoint(float64(inner), 0, sinA, cosA)
+	x2, y2 := rotatePoint(float64(outer), 0, sinA, cosA)
+	if width > 1 {
+		gfx.ThickLineColor(renderer, cx+int32(x1), cy+int32(y1), cx+int32(x2), cy+int32(y2), width, *colour)
+	} else {
+		gfx.LineColor(renderer, cx+int32(x1), cy+int32(y1), cx+int32(x2), cy+int32(y2), *colour)
+	}
+}
+
+/*
+Draw a needle of length 'length' from the centre at angle degrees clockwise from 3 o'clock.
+The needle is defined pointing at 3 o'clock and rotated in to place.
+*/
+func dialDrawNeedle(renderer *sdl.Renderer, cx, cy, length, width int32, angle float64, colour *sdl.Color) {
+	tail := float64(length) / 6
+	vxIn := []float64{-tail, 0, float64(length), 0}
+	vyIn := []float64{0, -float64(width), 0, float64(width)}
+	vx := make([]int16, len(vxIn))
+	vy := make([]int16, len(vyIn))
+	rad := angle * DEG_TO_RAD
+	sinA := math.Sin(rad)
+	cosA := math.Cos(rad)
+	for i := 0; i < len(vxIn); i++ {
+		px, py := rotatePoint(vxIn[i], vyIn[i], sinA, cosA)
+		vx[i] = int16(cx + int32(px))
+		vy[i] = int16(cy + int32(py))
+	}
+	gfx.FilledPolygonColor(renderer, vx, vy, *colour)
+	gfx.FilledCircleColor(renderer, cx, cy, width*2, *colour)
+}
+
+func ifLessUseN(i, n int32) int32 {
+	if i < n {
+		return n
+	}
+	return i
+}
@@ -0,0 +1,55 @@
+package go_sdl_widget
+
+import (
+	"testing"
+)
+
+func TestDialValueToAngle(t *testing.T) {
+	g := NewSDLGauge(0, 0, 100, 100, 1, 0, 100, 50, WIDGET_STYLE_DRAW_BORDER_AND_BG)
+	assertFloat(t, "Min angle", g.valueToAngle(0), 135)
+	assertFloat(t, "Mid angle", g.valueToAngle(50), 270)
+	assertFloat(t, "Max angle", g.valueToAngle(100), 405)
+	assertFloat(t, "Clamped angle", g.valueToAngle(200), 405)
+
+	g.SetValue(-10)
+	assertFloat(t, "Clamped min", g.GetValue(), 0)
+	g.SetValue(110)
+	assertFloat(t, "Clamped max", g.GetValue(), 100)
+	g.SetRange(10, 0)
+	assertFloat(t, "Clamped by range", g.GetValue(), 10)
+}
+
+func TestKnobDragAndWheel(t *testing.T) {
+	var calls int
+	k := NewSDLKnob(0, 0, 100, 100, 1, 0, 100, 50, WIDGET_STYLE_DRAW_BORDER_AND_BG, func(old, new float64) (float64, error) {
+		calls++
+		return new, nil
+	})
+	k.SetDragPixels(100)
+	md := &SDL_MouseData{dragging: true, draggingY: 200}
+	k.Click(md)
+	md.draggingY = 190
+	k.Click(md)
+	assertFloat(t, "Drag up 10px", k.GetValue(), 60)
+	md.draggingY = 220
+	k.Click(md)
+	assertFloat(t, "Drag down 20px", k.GetValue(), 30)
+	md.dragging = false
+	k.Click(md)
+
+	k.SetStep(5)
+	k.Wheel(2)
+	assertFloat(t, "Wheel up 2", k.GetValue(), 40)
+	k.Wheel(-100)
+	assertFloat(t, "Wheel down to min", k.GetValue(), 0)
+	if k.Wheel(-1) {
+		t.Error("Wheel at min should not change the value")
+	}
+	assertInt(t, "onChange calls", calls, 4)
+}
+
+func assertFloat(t *testing.T, message1 string, val, expected float64) {
+	if val != expected {
+		t.Errorf("%s: Actual %f Expected %f", message1, val, expected)
+	}
+}
@@ -1,6 +1,9 @@
 package go_sdl_widget
 
 import (
+	"math"
+	"sort"
+
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/veandco/go-sdl2/ttf"
 )
@@ -9,15 +12,26 @@ import (
 * Container for SDL_Widgets. A list of lists
 **/
 type SDL_WidgetGroup struct {
-	wigetLists []*SDL_WidgetSubGroup
-	font       *ttf.Font
+	wigetLists     []*SDL_WidgetSubGroup
+	font           *ttf.Font
+	mouseData      *SDL_MouseData
+	mouseDown      SDL_Widget // The widget the mouse button went down on. It gets the drag and release
+	hover          SDL_Widget // The widget under the mouse
+	modifiers      uint16     // Keyboard modifiers from the last key event. Passed to clicks
+	mouseX, mouseY int32      // Last known mouse position. Wheel events are sent to the widget under it
+	shortcuts      []*sdl_Shortcut
+	drag           *sdl_Drag // The drag and drop in progress
+	downX, downY   int32     // Where the mouse button went down. A drag starts when the mouse moves away from it
+	touch          *sdl_Touch
+	onCancel       func() bool // Called when B or Escape is not used. See SetOnCancel
+	skipTextInput  bool        // A shortcut used the last key down. The text input for that key is dropped
 }
 
 func NewWidgetGroup(font *ttf.Font) *SDL_WidgetGroup {
 	if font == nil {
 		font = GetResourceInstance().GetFont()
 	}
-	return &SDL_WidgetGroup{font: font, wigetLists: make([]*SDL_WidgetSubGroup, 0)}
+	return &SDL_WidgetGroup{font: font, wigetLists: make([]*SDL_WidgetSubGroup, 0), mouseData: &SDL_MouseData{}, touch: newTouch()}
 }
 
 func (wg *SDL_WidgetGroup) NewWidgetSubGroup(x, y, w, h, id int32, style STATE_BITS) *SDL_WidgetSubGroup {
@@ -98,6 +112,41 @@ func (wg *SDL_WidgetGroup) KeyPress(c int, ctrl, down bool) bool {
 	return false
 }
 
+/*
+Pass committed text from SDL text input to the focused widget.
+Not if it is from a key that was used by a shortcut, so a shortcut on a letter does not also type it
+*/
+func (wg *SDL_WidgetGroup) TextInput(e *sdl.TextInputEvent) bool {
+	if wg.skipTextInput {
+		wg.skipTextInput = false
+		return true
+	}
+	text := e.GetText()
+	for _, wl := range wg.wigetLists {
+		if wl.IsEnabled() {
+			if wl.TextInput(text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/*
+Pass the IME composition to the focused widget
+*/
+func (wg *SDL_WidgetGroup) TextEditing(e *sdl.TextEditingEvent) bool {
+	text := e.GetText()
+	for _, wl := range wg.wigetLists {
+		if wl.IsEnabled() {
+			if wl.TextEditing(text, e.Start, e.Length) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (wg *SDL_WidgetGroup) Scale(s float32) {
 	for _, wl := range wg.wigetLists {
 		wl.Scale(s)
@@ -108,6 +157,7 @@ func (wg *SDL_WidgetGroup) NextFrame() {
 	for _, wl := range wg.wigetLists {
 		wl.NextFrame()
 	}
+	wg.touchFrame()
 }
 
 func (wg *SDL_WidgetGroup) Destroy() {
@@ -117,11 +167,20 @@ func (wg *SDL_WidgetGroup) Destroy() {
 }
 
 func (wg *SDL_WidgetGroup) Draw(renderer *sdl.Renderer) {
+	if !wg.touch.sizeSet {
+		// The window size, not the renderer output size. Mouse positions are in window points, not pixels
+		if win, err := renderer.GetWindow(); err == nil {
+			wg.touch.w, wg.touch.h = win.GetSize()
+		}
+	}
 	for _, wl := range wg.wigetLists {
 		if wl.IsVisible() {
 			wl.Draw(renderer, wg.font)
 		}
 	}
+	if wg.drag != nil {
+		wg.drag.draw(renderer, wg.font, wg.mouseX, wg.mouseY)
+	}
 }
 
 func (wg *SDL_WidgetGroup) InsideWidget(x, y int32) SDL_Widget {
@@ -135,3 +194,394 @@ func (wg *SDL_WidgetGroup) InsideWidget(x, y int32) SDL_Widget {
 	}
 	return nil
 }
+
+/*
+Pass an event from sdl.PollEvent to the widgets. Returns true if a widget used it.
+
+Mouse button down goes to the widget under the mouse. It gets the focus if it can (clicking a widget
+that can not take the focus leaves it where it is) and clicking outside all widgets clears the focus.
+Mouse motion with the button down drags the widget the button went down on, until the button is released.
+If that widget is a drag source (see SDL_WidgetBase.SetDragSource) moving the left button more than a few pixels
+starts a drag and drop instead. The drop goes to the SDL_DropTarget under the mouse. Escape cancels it.
+Buttons and other clickable widgets are clicked on release, if the mouse is still on them.
+Mouse motion moves the hover state to the widget under the mouse. It is cleared when the mouse leaves the window.
+The wheel goes to the widget under the mouse. If it does not use it, it goes to the containers the widget is in.
+Keys go to the focused widget. Printable keys are left for the sdl.TextInputEvent if text input has been
+started, otherwise they are passed as KeyPress(char, false, true). Control keys, and any key with Ctrl held,
+are passed as KeyPress(code, true, down). Tab and Shift+Tab move the focus if the focused widget does not use them.
+The arrow keys, without modifiers, move the focus to the nearest widget in that direction (see FocusDirection)
+if the focused widget does not use them. Escape, if not used, calls the function given to SetOnCancel.
+Game controller: the D-pad moves the focus in the same way (it is not passed to the focused widget), A clicks
+the focused widget and B cancels a drag or press, closes an entry popup or calls the SetOnCancel function.
+If the window loses focus any drag or press is cancelled and the Ctrl and Shift keys are released.
+Touch: a tap clicks and a long press is a right click. A swipe in a scrollable container (SDL_FileList)
+scrolls it, and keeps scrolling for a while when the finger is lifted (see NextFrame). Otherwise moving the finger
+drags as the mouse would. Pinch zooms the SDL_Zoomable under it. Once a finger event has been handled, the mouse
+events SDL makes from touches are ignored. Touch positions are converted to window positions, see SetTouchSize.
+*/
+func (wg *SDL_WidgetGroup) HandleEvent(event sdl.Event) bool {
+	switch e := event.(type) {
+	case *sdl.MouseButtonEvent:
+		if wg.touch.seen && e.Which == sdl.TOUCH_MOUSEID {
+			return false
+		}
+		wg.mouseX, wg.mouseY = e.X, e.Y
+		if e.Type == sdl.MOUSEBUTTONDOWN {
+			return wg.mouseButtonDown(e)
+		}
+		return wg.mouseButtonUp(e)
+	case *sdl.MouseMotionEvent:
+		if wg.touch.seen && e.Which == sdl.TOUCH_MOUSEID {
+			return false
+		}
+		wg.mouseX, wg.mouseY = e.X, e.Y
+		wg.updateHover()
+		return wg.mouseMotion(e)
+	case *sdl.MouseWheelEvent:
+		if wg.touch.seen && e.Which == sdl.TOUCH_MOUSEID {
+			return false
+		}
+		return wg.mouseWheel(e)
+	case *sdl.TouchFingerEvent:
+		return wg.finger(e)
+	case *sdl.MultiGestureEvent:
+		return wg.gesture(e)
+	case *sdl.KeyboardEvent:
+		wg.modifiers = e.Keysym.Mod
+		return wg.keyboard(e)
+	case *sdl.TextInputEvent:
+		return wg.TextInput(e)
+	case *sdl.TextEditingEvent:
+		return wg.TextEditing(e)
+	case *sdl.WindowEvent:
+		return wg.window(e)
+	case *sdl.ControllerButtonEvent:
+		return wg.controllerButton(e)
+	}
+	return false
+}
+
+func (wg *SDL_WidgetGroup) mouseButtonDown(e *sdl.MouseButtonEvent) bool {
+	w := wg.InsideWidget(e.X, e.Y)
+	if w == nil {
+		wg.mouseDown = nil
+		wg.moveFocus(nil)
+		return false
+	}
+	if w.CanFocus() {
+		wg.moveFocus(w)
+	}
+	wg.mouseDown = w
+	wg.downX, wg.downY = e.X, e.Y
+	md := wg.mouseData.ActionMouseDown(e, w.GetWidgetId())
+	md.down = true
+	md.modifiers = wg.modifiers
+	w.Click(md)
+	return true
+}
+
+/*
+Unfocus the focused widget and focus w. Only the widgets that change are told
+*/
+func (wg *SDL_WidgetGroup) moveFocus(w SDL_Widget) {
+	for _, f := range wg.focusableWidgets() {
+		if f != w && f.IsFocused() {
+			f.SetFocused(false)
+		}
+	}
+	if w != nil && !w.IsFocused() {
+		w.SetFocused(true)
+	}
+}
+
+/*
+The widgets that Tab moves the focus through, in order. See SDL_WidgetBase.SetTabIndex
+*/
+func (wg *SDL_WidgetGroup) TabOrder() []SDL_Widget {
+	l := make([]SDL_Widget, 0)
+	for _, w := range wg.focusableWidgets() {
+		if w.GetTabIndex() >= 0 {
+			l = append(l, w)
+		}
+	}
+	sort.SliceStable(l, func(i, j int) bool {
+		ti := l[i].GetTabIndex()
+		tj := l[j].GetTabIndex()
+		if ti == 0 || tj == 0 {
+			return ti != 0 && tj == 0
+		}
+		return ti < tj
+	})
+	return l
+}
+
+/*
+Move the focus to the next widget in the tab order. From the last it goes to the first.
+Returns the focused widget or nil if no widgets can be focused
+*/
+func (wg *SDL_WidgetGroup) FocusNext() SDL_Widget {
+	return wg.focusStep(1)
+}
+
+/*
+Move the focus to the previous widget in the tab order. From the first it goes to the last.
+*/
+func (wg *SDL_WidgetGroup) FocusPrevious() SDL_Widget {
+	return wg.focusStep(-1)
+}
+
+func (wg *SDL_WidgetGroup) focusStep(d int) SDL_Widget {
+	l := wg.TabOrder()
+	if len(l) == 0 {
+		return nil
+	}
+	n := 0
+	if d < 0 {
+		n = len(l) - 1
+	}
+	for i, w := range l {
+		if w.IsFocused() {
+			n = (i + d + len(l)) % len(l)
+			break
+		}
+	}
+	wg.moveFocus(l[n])
+	return l[n]
+}
+
+/*
+All enabled and visible widgets that can be focused, in the order they were added. Includes widgets in containers
+*/
+func (wg *SDL_WidgetGroup) focusableWidgets() []SDL_Widget {
+	l := make([]SDL_Widget, 0)
+	for _, wl := range wg.wigetLists {
+		if wl.IsEnabled() && wl.IsVisible() {
+			l = appendFocusable(l, wl.ListWidgets())
+		}
+	}
+	return l
+}
+
+func appendFocusable(l []SDL_Widget, widgets []SDL_Widget) []SDL_Widget {
+	for _, w := range widgets {
+		if !w.IsEnabled() || !w.IsVisible() {
+			continue
+		}
+		wc, isContainer := w.(SDL_Container)
+		if isContainer {
+			l = appendFocusable(l, wc.ListWidgets())
+		} else if w.CanFocus() {
+			l = append(l, w)
+		}
+	}
+	return l
+}
+
+func (wg *SDL_WidgetGroup) mouseButtonUp(e *sdl.MouseButtonEvent) bool {
+	w := wg.mouseDown
+	md := wg.mouseData
+	md.down = false
+	if wg.drag != nil {
+		return wg.endDrag(e.X, e.Y)
+	}
+	wg.mouseDown = nil
+	if w == nil {
+		return false
+	}
+	if md.IsDragging() {
+		w.Click(md.ActionStopDragging(e))
+	} else {
+		md.setXY(e.X, e.Y)
+	}
+	w.Release(md)
+	md.ActionReset(e)
+	return true
+}
+
+func (wg *SDL_WidgetGroup) mouseMotion(e *sdl.MouseMotionEvent) bool {
+	w := wg.mouseDown
+	if w == nil || !wg.mouseData.IsDown() {
+		return false
+	}
+	if wg.drag != nil {
+		wg.moveDrag(e.X, e.Y)
+		return true
+	}
+	if wg.startDrag(e.X, e.Y) {
+		return true
+	}
+	w.Click(wg.mouseData.ActionStartDragging(e))
+	return true
+}
+
+/*
+Move the hover state to the enabled widget under the mouse
+*/
+func (wg *SDL_WidgetGroup) updateHover() {
+	w := wg.InsideWidget(wg.mouseX, wg.mouseY)
+	if w != nil && !w.IsEnabled() {
+		w = nil
+	}
+	wg.setHover(w)
+}
+
+func (wg *SDL_WidgetGroup) setHover(w SDL_Widget) {
+	if w == wg.hover {
+		return
+	}
+	if wg.hover != nil {
+		wg.hover.SetHover(false)
+	}
+	wg.hover = w
+	if w != nil {
+		w.SetHover(true)
+	}
+}
+
+func (wg *SDL_WidgetGroup) mouseWheel(e *sdl.MouseWheelEvent) bool {
+	dx, dy, precise := e.X, e.Y, e.PreciseY
+	if e.Direction == sdl.MOUSEWHEEL_FLIPPED {
+		dx, dy, precise = -dx, -dy, -precise
+	}
+	if dx == 0 && dy == 0 {
+		return false
+	}
+	return wg.Scroll(wg.mouseX, wg.mouseY, dx, dy, precise)
+}
+
+/*
+Scroll the widget at x,y. If it is not SDL_Scrollable, or does not use the scroll, it is passed to the
+container it is in and so on up to the sub group. Returns true if a widget used it.
+*/
+func (wg *SDL_WidgetGroup) Scroll(x, y, dx, dy int32, precise float32) bool {
+	return wg.bubbleAt(x, y, func(w SDL_Widget) bool {
+		s, ok := w.(SDL_Scrollable)
+		return ok && w.IsEnabled() && s.Scroll(dx, dy, precise)
+	}) != nil
+}
+
+/*
+Offer the widget at x,y to f, then the containers it is in, up to the sub group.
+Returns the first widget f returns true for, or nil
+*/
+func (wg *SDL_WidgetGroup) bubbleAt(x, y int32, f func(SDL_Widget) bool) SDL_Widget {
+	for _, wl := range wg.wigetLists {
+		if wl.IsEnabled() {
+			path := widgetPathAt(wl, x, y)
+			if len(path) == 0 {
+				continue
+			}
+			for i := len(path) - 1; i >= 0; i-- {
+				if f(path[i]) {
+					return path[i]
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+/*
+The containers from c down to the widget at x,y. Found the same way as SDL_WidgetSubGroup.Inside.
+Empty if there is no widget at x,y
+*/
+func widgetPathAt(c SDL_Container, x, y int32) []SDL_Widget {
+	w, ok := c.(SDL_Widget)
+	if !ok || !w.IsVisible() {
+		return nil
+	}
+	for _, ww := range c.ListWidgets() {
+		if wp, hasPopup := ww.(sdl_Popup); hasPopup && wp.insidePopup(x, y) {
+			return []SDL_Widget{w, ww}
+		}
+		wc, isContainer := ww.(SDL_Container)
+		if isContainer {
+			p := widgetPathAt(wc, x, y)
+			if len(p) > 0 {
+				return append([]SDL_Widget{w}, p...)
+			}
+		} else if isInsideRect(x, y, ww.GetRect()) {
+			return []SDL_Widget{w, ww}
+		}
+	}
+	return nil
+}
+
+func (wg *SDL_WidgetGroup) keyboard(e *sdl.KeyboardEvent) bool {
+	c := int(e.Keysym.Sym)
+	down := e.State == sdl.PRESSED
+	if wg.drag != nil && c == sdl.K_ESCAPE {
+		if down {
+			wg.CancelDrag()
+		}
+		return true
+	}
+	if down {
+		// SDL sends the text input after the key down, or none at all (for example with Ctrl)
+		wg.skipTextInput = false
+	}
+	if down && e.Repeat == 0 && wg.shortcut(NewKeyChord(c, e.Keysym.Mod)) {
+		wg.skipTextInput = true
+		return true
+	}
+	if c < 32 || c == 127 || c&0x40000000 != 0 || e.Keysym.Mod&sdl.KMOD_CTRL != 0 {
+		if wg.KeyPress(c, true, down) {
+			return true
+		}
+		// Tab not used by the focused widget moves the focus. Shift+Tab moves it back
+		if c == sdl.K_TAB && down && e.Keysym.Mod&sdl.KMOD_CTRL == 0 {
+			if e.Keysym.Mod&sdl.KMOD_SHIFT != 0 {
+				return wg.FocusPrevious() != nil
+			}
+			return wg.FocusNext() != nil
+		}
+		if !down || e.Keysym.Mod&(sdl.KMOD_CTRL|sdl.KMOD_SHIFT|sdl.KMOD_ALT|sdl.KMOD_GUI) != 0 {
+			return false
+		}
+		// Arrows not used by the focused widget move the focus that way
+		if d, ok := arrowKeyDirections[c]; ok {
+			return wg.FocusDirection(d) != nil
+		}
+		if c == sdl.K_ESCAPE && wg.onCancel != nil {
+			return wg.onCancel()
+		}
+		return false
+	}
+	if down && !GetResourceInstance().isTextInputActive() {
+		return wg.KeyPress(c, false, true)
+	}
+	return false
+}
+
+/*
+Stop any drag and release the pressed widget without clicking it
+*/
+func (wg *SDL_WidgetGroup) cancelPress() {
+	if wg.mouseDown != nil {
+		if wg.mouseData.IsDragging() {
+			wg.mouseDown.Click(wg.mouseData.ActionStopDragging(&sdl.MouseButtonEvent{X: wg.mouseX, Y: wg.mouseY}))
+		}
+		// Release off every widget so a pressed widget is not clicked
+		wg.mouseDown.Release(&SDL_MouseData{x: math.MinInt32, y: math.MinInt32})
+	}
+	wg.mouseDown = nil
+	wg.mouseData.down = false
+	wg.mouseData.ActionReset(&sdl.MouseButtonEvent{X: wg.mouseX, Y: wg.mouseY})
+}
+
+func (wg *SDL_WidgetGroup) window(e *sdl.WindowEvent) bool {
+	if e.Event == sdl.WINDOWEVENT_LEAVE {
+		wg.setHover(nil)
+	}
+	if e.Event == sdl.WINDOWEVENT_FOCUS_LOST {
+		wg.CancelDrag()
+		wg.cancelPress()
+		wg.modifiers = 0
+		// The key up events for modifiers held when focus was lost will not arrive
+		for _, c := range []int{sdl.K_LCTRL, sdl.K_RCTRL, sdl.K_LSHIFT, sdl.K_RSHIFT} {
+			wg.KeyPress(c, true, false)
+		}
+	}
+	return false
+}
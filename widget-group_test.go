@@ -0,0 +1,363 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func mouseDown(x, y int32) *sdl.MouseButtonEvent {
+	return &sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONDOWN, Button: sdl.BUTTON_LEFT, State: sdl.PRESSED, Clicks: 1, X: x, Y: y}
+}
+
+func mouseUp(x, y int32) *sdl.MouseButtonEvent {
+	return &sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONUP, Button: sdl.BUTTON_LEFT, State: sdl.RELEASED, Clicks: 1, X: x, Y: y}
+}
+
+func keyEvent(c sdl.Keycode, mod uint16, down bool) *sdl.KeyboardEvent {
+	e := &sdl.KeyboardEvent{Type: sdl.KEYUP, State: sdl.RELEASED}
+	if down {
+		e.Type = sdl.KEYDOWN
+		e.State = sdl.PRESSED
+	}
+	e.Keysym.Sym = c
+	e.Keysym.Mod = mod
+	return e
+}
+
+func textEvent(s string) *sdl.TextInputEvent {
+	e := &sdl.TextInputEvent{Type: sdl.TEXTINPUT}
+	copy(e.Text[:], s)
+	return e
+}
+
+func TestWidgetGroupHandleEvent(t *testing.T) {
+	clicks := 0
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 400, 200, 1, WIDGET_STYLE_DRAW_NONE)
+	e := sg.Add(NewSDLEntry(10, 10, 100, 24, 2, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Entry)
+	sg.Add(NewSDLButton(200, 10, 50, 24, 3, "OK", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, func(s string, id, x, y int32) bool {
+		clicks++
+		return true
+	}))
+	k := sg.Add(NewSDLKnob(300, 10, 50, 50, 4, 0, 100, 50, WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Knob)
+
+	assertBool(t, "Click entry", "consumed", wg.HandleEvent(mouseDown(20, 15)), true)
+	wg.HandleEvent(mouseUp(20, 15))
+	assertBool(t, "Click entry", "focused", e.IsFocused(), true)
+
+	assertBool(t, "Text input", "consumed", wg.HandleEvent(textEvent("abc")), true)
+	assertString(t, "Text input", e.GetText(), "abc")
+	assertBool(t, "Printable key left for text input", "consumed", wg.HandleEvent(keyEvent(sdl.K_x, 0, true)), false)
+	assertString(t, "Printable key left for text input", e.GetText(), "abc")
+	wg.HandleEvent(keyEvent(sdl.K_LEFT, 0, true))
+	wg.HandleEvent(keyEvent(sdl.K_LEFT, 0, false))
+	wg.HandleEvent(keyEvent(sdl.K_BACKSPACE, 0, true))
+	assertString(t, "Backspace", e.GetText(), "ac")
+
+	wg.HandleEvent(keyEvent(sdl.K_LCTRL, sdl.KMOD_LCTRL, true))
+	wg.HandleEvent(keyEvent(sdl.K_a, sdl.KMOD_LCTRL, true))
+	wg.HandleEvent(keyEvent(sdl.K_a, sdl.KMOD_LCTRL, false))
+	wg.HandleEvent(keyEvent(sdl.K_LCTRL, 0, false))
+	assertString(t, "Ctrl+A", e.GetSelectedText(), "ac")
+
+	wg.HandleEvent(mouseDown(210, 15))
+	wg.HandleEvent(mouseUp(210, 15))
+	assertInt(t, "Button clicked", clicks, 1)
+	assertBool(t, "Button takes focus", "entry focused", e.IsFocused(), false)
+	wg.HandleEvent(mouseDown(20, 15))
+	wg.HandleEvent(mouseUp(20, 15))
+	e.SetText("")
+
+	// Drag select. Runes are 10 pixels. Text starts at 20
+	wg.HandleEvent(textEvent("abcdefgh"))
+	layoutEntry(e)
+	wg.HandleEvent(mouseDown(26, 15))
+	wg.HandleEvent(mouseUp(26, 15))
+	assertInt(t, "Click sets cursor", e.cursor, 1)
+	wg.HandleEvent(mouseDown(26, 15))
+	wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 46, Y: 15})
+	wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 300, Y: 100})
+	wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 56, Y: 15})
+	assertString(t, "Drag select", e.GetSelectedText(), "bcd")
+	wg.HandleEvent(mouseUp(56, 15))
+	assertBool(t, "Drag done", "dragging", e.dragging, false)
+	assertString(t, "Selection kept", e.GetSelectedText(), "bcd")
+	if wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 36, Y: 15}) {
+		t.Error("Motion without a button down should not be consumed")
+	}
+	assertString(t, "Selection kept after move", e.GetSelectedText(), "bcd")
+
+	assertBool(t, "Wheel over entry", "consumed", wg.HandleEvent(&sdl.MouseWheelEvent{Type: sdl.MOUSEWHEEL, Y: 1}), false)
+	wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 320, Y: 30})
+	assertBool(t, "Wheel over knob", "consumed", wg.HandleEvent(&sdl.MouseWheelEvent{Type: sdl.MOUSEWHEEL, Y: 2}), true)
+	assertFloat(t, "Wheel over knob", k.GetValue(), 52)
+	wg.HandleEvent(&sdl.MouseWheelEvent{Type: sdl.MOUSEWHEEL, Y: 1, Direction: sdl.MOUSEWHEEL_FLIPPED})
+	assertFloat(t, "Wheel flipped", k.GetValue(), 51)
+
+	wg.HandleEvent(keyEvent(sdl.K_LCTRL, sdl.KMOD_LCTRL, true))
+	assertBool(t, "Ctrl down", "ctrlKeyDown", e.ctrlKeyDown, true)
+	wg.HandleEvent(&sdl.WindowEvent{Type: sdl.WINDOWEVENT, Event: sdl.WINDOWEVENT_FOCUS_LOST})
+	assertBool(t, "Ctrl released on focus lost", "ctrlKeyDown", e.ctrlKeyDown, false)
+
+	assertBool(t, "Click outside", "consumed", wg.HandleEvent(mouseDown(150, 150)), false)
+	assertBool(t, "Click outside", "focused", e.IsFocused(), false)
+	assertBool(t, "Key with no focus", "consumed", wg.HandleEvent(keyEvent(sdl.K_LEFT, 0, true)), false)
+}
+
+func TestWidgetGroupTabFocus(t *testing.T) {
+	clicks := 0
+	onClick := func(s string, id, x, y int32) bool {
+		clicks++
+		return true
+	}
+	wg := NewWidgetGroup(nil)
+	sg1 := wg.NewWidgetSubGroup(0, 0, 400, 100, 1, WIDGET_STYLE_DRAW_NONE)
+	e := sg1.Add(NewSDLEntry(10, 10, 100, 24, 2, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil))
+	b1 := sg1.Add(NewSDLButton(200, 10, 50, 24, 3, "B1", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick))
+	sg1.Add(NewSDLLabel(260, 10, 50, 24, 4, "L", ALIGN_LEFT, WIDGET_STYLE_DRAW_NONE))
+	sg2 := wg.NewWidgetSubGroup(0, 100, 400, 100, 5, WIDGET_STYLE_DRAW_NONE)
+	k := sg2.Add(NewSDLKnob(10, 110, 50, 50, 6, 0, 100, 50, WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Knob)
+	nested := NewWidgetSubGroup(100, 110, 100, 50, 7, nil, WIDGET_STYLE_DRAW_NONE)
+	b2 := nested.Add(NewSDLButton(100, 110, 50, 24, 8, "B2", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick))
+	sg2.Add(nested)
+
+	ids := func() []int32 {
+		l := make([]int32, 0)
+		for _, w := range wg.TabOrder() {
+			l = append(l, w.GetWidgetId())
+		}
+		return l
+	}
+	assertString(t, "Tab order added", fmt.Sprint(ids()), "[2 3 6 8]")
+
+	tab := func(shift bool) {
+		var mod uint16
+		if shift {
+			mod = sdl.KMOD_LSHIFT
+		}
+		wg.HandleEvent(keyEvent(sdl.K_TAB, mod, true))
+		wg.HandleEvent(keyEvent(sdl.K_TAB, mod, false))
+	}
+	tab(false)
+	assertBool(t, "Tab from nothing", "entry focused", e.IsFocused(), true)
+	tab(false)
+	assertBool(t, "Tab", "b1 focused", b1.IsFocused(), true)
+	assertBool(t, "Tab", "entry focused", e.IsFocused(), false)
+	tab(false)
+	tab(false)
+	assertBool(t, "Tab in to container", "b2 focused", b2.IsFocused(), true)
+	tab(false)
+	assertBool(t, "Tab wraps", "entry focused", e.IsFocused(), true)
+	tab(true)
+	assertBool(t, "Shift+Tab wraps", "b2 focused", b2.IsFocused(), true)
+	tab(true)
+	assertBool(t, "Shift+Tab", "knob focused", k.IsFocused(), true)
+
+	wg.HandleEvent(keyEvent(sdl.K_UP, 0, true))
+	assertFloat(t, "Up turns focused knob", k.GetValue(), 51)
+	wg.HandleEvent(keyEvent(sdl.K_LEFT, 0, true))
+	wg.HandleEvent(keyEvent(sdl.K_LEFT, 0, true))
+	assertFloat(t, "Left turns focused knob", k.GetValue(), 49)
+
+	tab(false)
+	wg.HandleEvent(keyEvent(sdl.K_SPACE, 0, true))
+	wg.HandleEvent(keyEvent(sdl.K_SPACE, 0, false))
+	assertInt(t, "Space clicks focused button", clicks, 1)
+	wg.HandleEvent(keyEvent(sdl.K_RETURN, 0, true))
+	assertInt(t, "Return clicks focused button", clicks, 2)
+
+	k.SetTabIndex(1)
+	b2.SetTabIndex(2)
+	b1.SetTabIndex(-1)
+	assertString(t, "Tab order with index", fmt.Sprint(ids()), "[6 8 2]")
+	b2.SetEnabled(false)
+	assertString(t, "Disabled not in tab order", fmt.Sprint(ids()), "[6 2]")
+	e.SetCanFocus(false)
+	assertString(t, "Can not focus not in tab order", fmt.Sprint(ids()), "[6]")
+
+	wg.HandleEvent(mouseDown(210, 15))
+	wg.HandleEvent(mouseUp(210, 15))
+	assertBool(t, "Click focuses widget not in tab order", "b1 focused", b1.IsFocused(), true)
+	assertBool(t, "Click focuses widget not in tab order", "knob focused", k.IsFocused(), false)
+}
+
+/*
+Shift goes down in one entry and up in the next. The first must not think it is still down
+*/
+func TestWidgetGroupShiftTabModifiers(t *testing.T) {
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 400, 100, 1, WIDGET_STYLE_DRAW_NONE)
+	a := sg.Add(NewSDLEntry(10, 10, 100, 24, 2, "abc", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Entry)
+	b := sg.Add(NewSDLEntry(10, 40, 100, 24, 3, "def", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Entry)
+	wg.SetFocusedId(2)
+	wg.HandleEvent(keyEvent(sdl.K_LSHIFT, sdl.KMOD_LSHIFT, true))
+	wg.HandleEvent(keyEvent(sdl.K_TAB, sdl.KMOD_LSHIFT, true))
+	assertBool(t, "Shift+Tab", "b focused", b.IsFocused(), true)
+	wg.HandleEvent(keyEvent(sdl.K_TAB, sdl.KMOD_LSHIFT, false))
+	wg.HandleEvent(keyEvent(sdl.K_LSHIFT, 0, false))
+	wg.HandleEvent(keyEvent(sdl.K_TAB, 0, true))
+	assertBool(t, "Tab", "a focused", a.IsFocused(), true)
+	wg.HandleEvent(keyEvent(sdl.K_HOME, 0, true))
+	wg.HandleEvent(keyEvent(sdl.K_RIGHT, 0, true))
+	wg.HandleEvent(keyEvent(sdl.K_RIGHT, 0, true))
+	assertString(t, "Right Right", a.GetSelectedText(), "")
+	assertInt(t, "Right Right", a.cursor, 2)
+}
+
+func TestWidgetGroupHover(t *testing.T) {
+	events := ""
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 400, 100, 1, WIDGET_STYLE_DRAW_NONE)
+	b1 := sg.Add(NewSDLButton(10, 10, 50, 24, 2, "B1", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, nil))
+	b2 := sg.Add(NewSDLButton(100, 10, 50, 24, 3, "B2", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, nil))
+	for _, b := range []SDL_Widget{b1, b2} {
+		b.SetOnMouseEnter(func(w SDL_Widget) {
+			events = events + fmt.Sprintf("+%d", w.GetWidgetId())
+		})
+		b.SetOnMouseLeave(func(w SDL_Widget) {
+			events = events + fmt.Sprintf("-%d", w.GetWidgetId())
+		})
+	}
+	move := func(x, y int32) {
+		wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: x, Y: y})
+	}
+	move(20, 20)
+	move(25, 20)
+	assertBool(t, "Over b1", "hover", b1.IsHover(), true)
+	move(110, 20)
+	assertBool(t, "Over b2", "b1 hover", b1.IsHover(), false)
+	assertBool(t, "Over b2", "b2 hover", b2.IsHover(), true)
+	move(300, 80)
+	assertBool(t, "Over nothing", "b2 hover", b2.IsHover(), false)
+	move(110, 20)
+	wg.HandleEvent(&sdl.WindowEvent{Type: sdl.WINDOWEVENT, Event: sdl.WINDOWEVENT_LEAVE})
+	assertBool(t, "Left window", "b2 hover", b2.IsHover(), false)
+	b1.SetEnabled(false)
+	move(20, 20)
+	assertBool(t, "Disabled", "b1 hover", b1.IsHover(), false)
+	assertString(t, "Enter and leave", events, "+2-2+3-3+3-3")
+}
+
+func TestWidgetGroupClickOnRelease(t *testing.T) {
+	clicks := 0
+	onClick := func(s string, id, x, y int32) bool {
+		clicks++
+		return true
+	}
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 400, 100, 1, WIDGET_STYLE_DRAW_NONE)
+	b := sg.Add(NewSDLButton(10, 10, 50, 24, 2, "B", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick))
+	db := sg.Add(NewSDLButton(100, 10, 50, 24, 3, "DB", WIDGET_STYLE_DRAW_BORDER_AND_BG, 100, onClick))
+
+	left := 0
+	b.SetOnMouseLeave(func(w SDL_Widget) {
+		left++
+	})
+	wg.HandleEvent(mouseMove(20, 20))
+	wg.HandleEvent(mouseDown(20, 20))
+	assertBool(t, "Pressed", "pressed", b.IsPressed(), true)
+	assertInt(t, "Pressed, not clicked yet", clicks, 0)
+	// Held down it is still enabled, so it is not drawn disabled and does not lose the hover
+	assertBool(t, "Pressed", "enabled", b.IsEnabled(), true)
+	assertBool(t, "Pressed", "disabled colour", b.(*SDL_Button).getResourceColourStateIndex() == WIDGET_COLOUR_INDEX_DISABLE, false)
+	wg.HandleEvent(mouseMove(25, 20))
+	assertBool(t, "Moved while pressed", "hover", b.IsHover(), true)
+	assertInt(t, "Moved while pressed", left, 0)
+	wg.HandleEvent(mouseUp(25, 20))
+	assertInt(t, "Released", clicks, 1)
+	assertBool(t, "Released", "pressed", b.IsPressed(), false)
+
+	wg.HandleEvent(mouseDown(20, 20))
+	wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 80, Y: 20})
+	assertBool(t, "Dragged off", "pressed", b.IsPressed(), false)
+	wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 30, Y: 20})
+	assertBool(t, "Dragged back on", "pressed", b.IsPressed(), true)
+	wg.HandleEvent(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 80, Y: 20})
+	wg.HandleEvent(mouseUp(80, 20))
+	assertInt(t, "Released off the button is cancelled", clicks, 1)
+	assertBool(t, "Released off the button", "pressed", b.IsPressed(), false)
+
+	wg.HandleEvent(mouseDown(20, 20))
+	wg.HandleEvent(&sdl.WindowEvent{Type: sdl.WINDOWEVENT, Event: sdl.WINDOWEVENT_FOCUS_LOST})
+	assertBool(t, "Focus lost", "pressed", b.IsPressed(), false)
+	wg.HandleEvent(mouseUp(20, 20))
+	assertInt(t, "Focus lost cancels the click", clicks, 1)
+
+	wg.HandleEvent(mouseDown(110, 20))
+	wg.HandleEvent(mouseUp(110, 20))
+	assertInt(t, "Debounced button", clicks, 2)
+	assertBool(t, "Debounced button held", "clicked", db.IsClicked(), true)
+	wg.HandleEvent(mouseDown(110, 20))
+	wg.HandleEvent(mouseUp(110, 20))
+	assertInt(t, "Debounced button ignores clicks while held", clicks, 2)
+}
+
+func TestWidgetGroupClickEvents(t *testing.T) {
+	clicks := ""
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 400, 100, 1, WIDGET_STYLE_DRAW_NONE)
+	b := sg.Add(NewSDLButton(10, 10, 50, 24, 2, "B", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, func(s string, id, x, y int32) bool {
+		clicks = clicks + "onClick:"
+		return true
+	})).(*SDL_Button)
+	listener := func(name string) func(*SDL_ClickEvent) bool {
+		return func(ev *SDL_ClickEvent) bool {
+			clicks = clicks + fmt.Sprintf("%s(%s %d %d %d %t):", name, ev.GetText(), ev.GetButton(), ev.GetClickCount(), ev.GetX(), ev.IsCtrlDown())
+			return true
+		}
+	}
+	h1 := b.AddClickListener(CLICK_KIND_CLICK, listener("L1"))
+	b.AddClickListener(CLICK_KIND_CLICK, listener("L2"))
+	b.AddRightClickListener(listener("R"))
+	b.AddMiddleClickListener(listener("M"))
+	b.AddDoubleClickListener(listener("D"))
+	click := func(button, count uint8, x int32) {
+		wg.HandleEvent(&sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONDOWN, Button: button, State: sdl.PRESSED, Clicks: count, X: x, Y: 20})
+		wg.HandleEvent(&sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONUP, Button: button, State: sdl.RELEASED, Clicks: count, X: x, Y: 20})
+	}
+	click(sdl.BUTTON_LEFT, 1, 20)
+	assertString(t, "Left", clicks, "onClick:L1(B 1 1 20 false):L2(B 1 1 20 false):")
+	clicks = ""
+	click(sdl.BUTTON_LEFT, 2, 21)
+	assertString(t, "Double", clicks, "onClick:L1(B 1 2 21 false):L2(B 1 2 21 false):D(B 1 2 21 false):")
+	clicks = ""
+	click(sdl.BUTTON_RIGHT, 1, 22)
+	assertString(t, "Right", clicks, "R(B 3 1 22 false):")
+	clicks = ""
+	click(sdl.BUTTON_MIDDLE, 1, 23)
+	assertString(t, "Middle", clicks, "M(B 2 1 23 false):")
+
+	clicks = ""
+	wg.HandleEvent(keyEvent(sdl.K_LCTRL, sdl.KMOD_LCTRL, true))
+	click(sdl.BUTTON_LEFT, 1, 24)
+	wg.HandleEvent(keyEvent(sdl.K_LCTRL, 0, false))
+	assertString(t, "Ctrl", clicks, "onClick:L1(B 1 1 24 true):L2(B 1 1 24 true):")
+
+	assertBool(t, "Remove", "removed", b.RemoveClickListener(h1), true)
+	assertBool(t, "Remove again", "removed", b.RemoveClickListener(h1), false)
+	clicks = ""
+	click(sdl.BUTTON_LEFT, 1, 25)
+	assertString(t, "Removed", clicks, "onClick:L2(B 1 1 25 false):")
+
+	e := sg.Add(NewSDLEntry(100, 10, 100, 24, 3, "E", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Entry)
+	e.AddRightClickListener(listener("ER"))
+	clicks = ""
+	click(sdl.BUTTON_RIGHT, 1, 110)
+	assertString(t, "Entry right", clicks, "ER(E 3 1 110 false):")
+
+	e.AddClickListener(CLICK_KIND_CLICK, listener("EL"))
+	e.AddDoubleClickListener(listener("ED"))
+	clicks = ""
+	click(sdl.BUTTON_LEFT, 1, 110)
+	assertString(t, "Entry left", clicks, "EL(E 1 1 110 false):")
+	clicks = ""
+	click(sdl.BUTTON_LEFT, 2, 111)
+	assertString(t, "Entry double", clicks, "EL(E 1 2 111 false):ED(E 1 2 111 false):")
+	clicks = ""
+	wg.HandleEvent(mouseDown(110, 20))
+	wg.HandleEvent(mouseMove(150, 20))
+	wg.HandleEvent(mouseUp(150, 20))
+	assertString(t, "Entry drag select", clicks, "")
+}
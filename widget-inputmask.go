@@ -0,0 +1,234 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+/*
+Input mask slot chars:
+
+	9 a digit
+	a a letter
+	h a hex digit
+	* a letter or digit
+	\ the next char is a literal
+
+Any other char is a literal that is shown as is and skipped by the cursor.
+*/
+const (
+	ENTRY_INPUT_MASK_DATE                     = "99/99/9999"
+	ENTRY_INPUT_MASK_TIME                     = "99:99"
+	ENTRY_INPUT_MASK_TIME_SECONDS             = "99:99:99"
+	ENTRY_INPUT_MASK_PHONE                    = "(999) 999-9999"
+	ENTRY_INPUT_MASK_IP                       = "999.999.999.999"
+	ENTRY_INPUT_MASK_HEX_BYTE                 = "hh"
+	ENTRY_INPUT_MASK_HEX_WORD                 = "hhhh"
+	ENTRY_INPUT_MASK_HEX_COLOUR               = "#hhhhhh"
+	ENTRY_DEFAULT_INPUT_MASK_PLACEHOLDER rune = '_'
+)
+
+type sdl_InputMask struct {
+	mask        string
+	literal     []rune // The literal at each position. 0 if it is a slot
+	class       []rune // The slot class (9 a h *) at each position. 0 if it is a literal
+	placeholder rune
+}
+
+func newInputMask(mask string, placeholder rune) (*sdl_InputMask, error) {
+	m := &sdl_InputMask{mask: mask, placeholder: placeholder}
+	escaped := false
+	slots := 0
+	for _, r := range mask {
+		if escaped {
+			m.literal = append(m.literal, r)
+			m.class = append(m.class, 0)
+			escaped = false
+			continue
+		}
+		switch r {
+		case '\\':
+			escaped = true
+		case '9', 'a', 'h', '*':
+			m.literal = append(m.literal, 0)
+			m.class = append(m.class, r)
+			slots++
+		default:
+			m.literal = append(m.literal, r)
+			m.class = append(m.class, 0)
+		}
+	}
+	if slots == 0 {
+		return nil, fmt.Errorf("input mask '%s' has no input slots", mask)
+	}
+	return m, nil
+}
+
+func (m *sdl_InputMask) size() int {
+	return len(m.class)
+}
+
+func (m *sdl_InputMask) isSlot(i int) bool {
+	return i >= 0 && i < len(m.class) && m.class[i] != 0
+}
+
+/*
+Does the slot at i accept r
+*/
+func (m *sdl_InputMask) accepts(i int, r rune) bool {
+	if !m.isSlot(i) {
+		return false
+	}
+	switch m.class[i] {
+	case '9':
+		return unicode.IsDigit(r)
+	case 'a':
+		return unicode.IsLetter(r)
+	case 'h':
+		return strings.ContainsRune("0123456789abcdefABCDEF", r)
+	default:
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+}
+
+/*
+The first slot at or after i. size() if there are none
+*/
+func (m *sdl_InputMask) nextSlot(i int) int {
+	if i < 0 {
+		i = 0
+	}
+	for ; i < len(m.class); i++ {
+		if m.class[i] != 0 {
+			return i
+		}
+	}
+	return len(m.class)
+}
+
+/*
+The last slot before i. -1 if there are none
+*/
+func (m *sdl_InputMask) prevSlot(i int) int {
+	if i > len(m.class) {
+		i = len(m.class)
+	}
+	for i = i - 1; i >= 0; i-- {
+		if m.class[i] != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+/*
+The mask with all slots empty
+*/
+func (m *sdl_InputMask) empty() []rune {
+	r := make([]rune, len(m.class))
+	for i := range m.class {
+		if m.class[i] == 0 {
+			r[i] = m.literal[i]
+		} else {
+			r[i] = m.placeholder
+		}
+	}
+	return r
+}
+
+/*
+Type text in to the slots starting at pos. Chars that match a slot fill it. A char that matches a
+following literal moves past it (so 1.2 in an IP mask works). Anything else is rejected.
+Returns the new runes and the cursor position after the last char.
+*/
+func (m *sdl_InputMask) fill(runes []rune, pos int, text string) ([]rune, int) {
+	out := make([]rune, len(runes))
+	copy(out, runes)
+	for _, r := range text {
+		i := m.nextSlot(pos)
+		if i < len(out) && m.accepts(i, r) {
+			out[i] = r
+			pos = m.nextSlot(i + 1)
+			continue
+		}
+		for j := pos; j < len(out); j++ {
+			if m.class[j] == 0 && m.literal[j] == r {
+				pos = m.nextSlot(j + 1)
+				break
+			}
+		}
+	}
+	return out, pos
+}
+
+/*
+Put any text in to the mask. If it is already formatted for the mask it is kept
+otherwise its chars are typed in to an empty mask.
+*/
+func (m *sdl_InputMask) conform(text string) []rune {
+	runes := []rune(text)
+	if len(runes) == len(m.class) {
+		ok := true
+		for i, r := range runes {
+			if m.class[i] == 0 && r != m.literal[i] || m.class[i] != 0 && r != m.placeholder && !m.accepts(i, r) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return runes
+		}
+	}
+	out, _ := m.fill(m.empty(), 0, text)
+	return out
+}
+
+/*
+Empty the slots from (inclusive) to too (exclusive). Literals are left in place
+*/
+func (m *sdl_InputMask) clear(runes []rune, from, too int) []rune {
+	out := make([]rune, len(runes))
+	copy(out, runes)
+	for i := from; i < too && i < len(out); i++ {
+		if m.isSlot(i) {
+			out[i] = m.placeholder
+		}
+	}
+	return out
+}
+
+/*
+Only the chars typed in to slots. No literals or placeholders
+*/
+func (m *sdl_InputMask) raw(runes []rune) string {
+	var sb strings.Builder
+	for i, r := range runes {
+		if m.isSlot(i) && r != m.placeholder {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+/*
+The text with the placeholders removed
+*/
+func (m *sdl_InputMask) formatted(runes []rune) string {
+	var sb strings.Builder
+	for i, r := range runes {
+		if !(m.isSlot(i) && r == m.placeholder) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func (m *sdl_InputMask) complete(runes []rune) bool {
+	for i, r := range runes {
+		if m.isSlot(i) && r == m.placeholder {
+			return false
+		}
+	}
+	return true
+}
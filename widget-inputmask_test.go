@@ -0,0 +1,94 @@
+package go_sdl_widget
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestInputMaskParse(t *testing.T) {
+	_, err := newInputMask("--", '_')
+	if err == nil {
+		t.Errorf("Mask with no slots should return an error")
+	}
+	m, err := newInputMask("\\99-h", '_')
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+	assertInt(t, "Size", m.size(), 4)
+	assertString(t, "Empty", string(m.empty()), "9_-_")
+	assertBool(t, "Escaped", "isSlot", m.isSlot(0), false)
+	assertBool(t, "Hex", "accepts", m.accepts(3, 'F'), true)
+	assertBool(t, "Hex", "accepts", m.accepts(3, 'g'), false)
+	assertInt(t, "Next slot", m.nextSlot(2), 3)
+	assertInt(t, "Prev slot", m.prevSlot(3), 1)
+	assertInt(t, "No prev slot", m.prevSlot(1), -1)
+
+	ip, _ := newInputMask(ENTRY_INPUT_MASK_IP, '_')
+	r, pos := ip.fill(ip.empty(), 0, "10.1.255.3")
+	assertString(t, "IP fill", string(r), "10_.1__.255.3__")
+	assertInt(t, "IP pos", pos, 13)
+	assertString(t, "IP formatted", ip.formatted(r), "10.1.255.3")
+	assertString(t, "IP raw", ip.raw(r), "1012553")
+	assertString(t, "Conform formatted", string(ip.conform("10_.1__.255.3__")), "10_.1__.255.3__")
+	assertString(t, "Conform raw", string(ip.conform("1.2")), "1__.2__.___.___")
+}
+
+func TestEntryInputMask(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	err := e.SetInputMask(ENTRY_INPUT_MASK_PHONE)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+	assertString(t, "Placeholders", e.GetText(), "(___) ___-____")
+	assertInt(t, "Cursor on first slot", e.cursor, 1)
+	typeString(e, "01x2")
+	assertString(t, "Reject", e.GetText(), "(012) ___-____")
+	assertInt(t, "Skip literals", e.cursor, 6)
+	typeString(e, "3456789")
+	assertString(t, "Full", e.GetText(), "(012) 345-6789")
+	assertBool(t, "Complete", "IsInputMaskComplete", e.IsInputMaskComplete(), true)
+	assertString(t, "Raw", e.GetRawText(), "0123456789")
+	typeString(e, "0")
+	assertString(t, "No more slots", e.GetText(), "(012) 345-6789")
+
+	e.KeyPress(sdl.K_HOME, true, true)
+	e.KeyPress(sdl.K_RIGHT, true, true)
+	e.KeyPress(sdl.K_RIGHT, true, true)
+	e.KeyPress(sdl.K_RIGHT, true, true)
+	assertInt(t, "Right skips literals", e.cursor, 6)
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertString(t, "Backspace empties slot", e.GetText(), "(01_) 345-6789")
+	assertInt(t, "Backspace cursor", e.cursor, 3)
+	e.KeyPress(sdl.K_DELETE, true, true)
+	assertString(t, "Delete", e.GetText(), "(01_) 345-6789")
+	e.KeyPress(sdl.K_RIGHT, true, true)
+	e.KeyPress(sdl.K_DELETE, true, true)
+	assertString(t, "Delete skips literal", e.GetText(), "(01_) _45-6789")
+	assertString(t, "Formatted", e.GetFormattedText(), "(01) 45-6789")
+	assertBool(t, "Not complete", "IsInputMaskComplete", e.IsInputMaskComplete(), false)
+
+	mc := useMemoryClipboard(t)
+	e.SetSelectedTextBounds(1, 13)
+	mc.SetText("5551234")
+	ctrlKey(e, sdl.K_v)
+	assertString(t, "Paste over selection", e.GetText(), "(555) 123-4___")
+
+	e.SetSelectedTextBounds(1, 13)
+	mc.SetText("555-1234")
+	ctrlKey(e, sdl.K_v)
+	assertString(t, "Paste skips to literal", e.GetText(), "(555) ___-1234")
+	ctrlKey(e, sdl.K_z)
+
+	e.SetInputMaskPlaceholder('#')
+	assertString(t, "Placeholder", e.GetText(), "(555) 123-4###")
+	e.SetText("01/02/2024")
+	assertString(t, "SetText conforms", e.GetText(), "(010) 220-24##")
+
+	e.SetInputMask(ENTRY_INPUT_MASK_DATE)
+	e.SetText("")
+	typeString(e, "1/")
+	assertString(t, "Typed literal", e.GetText(), "1#/##/####")
+	assertInt(t, "Typed literal cursor", e.cursor, 3)
+}
@@ -0,0 +1,413 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+type VKBD_LAYER int
+type VKBD_ACTION int
+
+const (
+	VKBD_LAYER_LOWER VKBD_LAYER = iota
+	VKBD_LAYER_UPPER
+	VKBD_LAYER_SYMBOL
+	VKBD_LAYER_COUNT int = 3 // So we create an array the right size
+
+	VKBD_ACTION_CHAR VKBD_ACTION = iota
+	VKBD_ACTION_CTRL
+	VKBD_ACTION_SHIFT
+	VKBD_ACTION_CAPS
+	VKBD_ACTION_SYMBOL
+	VKBD_ACTION_LETTERS
+	VKBD_ACTION_HIDE
+
+	vkbd_PRESSED_MS uint64 = 150 // How long a tapped key is highlighted
+)
+
+/*
+Special keys in a layout row. Any other key is a character.
+*/
+var vkbdSpecialKeys = map[string]*sdl_VKey{
+	"{bs}":    {label: "Back", action: VKBD_ACTION_CTRL, code: sdl.K_BACKSPACE},
+	"{del}":   {label: "Del", action: VKBD_ACTION_CTRL, code: sdl.K_DELETE},
+	"{enter}": {label: "Enter", action: VKBD_ACTION_CTRL, code: sdl.K_RETURN},
+	"{tab}":   {label: "Tab", action: VKBD_ACTION_CTRL, code: sdl.K_TAB},
+	"{left}":  {label: "<", action: VKBD_ACTION_CTRL, code: sdl.K_LEFT},
+	"{right}": {label: ">", action: VKBD_ACTION_CTRL, code: sdl.K_RIGHT},
+	"{space}": {label: "Space", text: " ", action: VKBD_ACTION_CHAR, code: ' '},
+	"{shift}": {label: "Shift", action: VKBD_ACTION_SHIFT},
+	"{caps}":  {label: "Caps", action: VKBD_ACTION_CAPS},
+	"{sym}":   {label: "?123", action: VKBD_ACTION_SYMBOL},
+	"{abc}":   {label: "abc", action: VKBD_ACTION_LETTERS},
+	"{hide}":  {label: "Hide", action: VKBD_ACTION_HIDE},
+}
+
+/****************************************************************************************
+* SDL_KeyboardLayout code
+* One list of rows for each layer. Keys in a row are separated by a single space.
+* A key can have a relative width appended. For example '{space}:5' is 5 keys wide.
+* Special keys are: {bs} {del} {enter} {tab} {left} {right} {space} {shift} {caps} {sym} {abc} {hide}
+**/
+type SDL_KeyboardLayout struct {
+	Layers [VKBD_LAYER_COUNT][]string
+}
+
+func NewDefaultKeyboardLayout() *SDL_KeyboardLayout {
+	kl := &SDL_KeyboardLayout{}
+	kl.Layers[VKBD_LAYER_LOWER] = []string{
+		"1 2 3 4 5 6 7 8 9 0 {bs}:1.5",
+		"q w e r t y u i o p {del}:1.5",
+		"{caps}:1.5 a s d f g h j k l {enter}:1.5",
+		"{shift}:2 z x c v b n m , . /",
+		"{sym}:1.5 {left} {space}:6 {right} {hide}:1.5",
+	}
+	kl.Layers[VKBD_LAYER_UPPER] = []string{
+		"1 2 3 4 5 6 7 8 9 0 {bs}:1.5",
+		"Q W E R T Y U I O P {del}:1.5",
+		"{caps}:1.5 A S D F G H J K L {enter}:1.5",
+		"{shift}:2 Z X C V B N M , . /",
+		"{sym}:1.5 {left} {space}:6 {right} {hide}:1.5",
+	}
+	kl.Layers[VKBD_LAYER_SYMBOL] = []string{
+		"1 2 3 4 5 6 7 8 9 0 {bs}:1.5",
+		"! @ # $ % ^ & * ( ) {del}:1.5",
+		"- _ = + [ ] { } ; : {enter}:1.5",
+		"' \" ` ~ \\ | < > ? €",
+		"{abc}:1.5 {left} {space}:6 {right} {hide}:1.5",
+	}
+	return kl
+}
+
+/****************************************************************************************
+* sdl_VKey code
+* A single key on the virtual keyboard
+**/
+type sdl_VKey struct {
+	label  string
+	text   string
+	code   int
+	action VKBD_ACTION
+	width  float32
+}
+
+func parseVKeyRow(row string) ([]*sdl_VKey, error) {
+	keys := make([]*sdl_VKey, 0)
+	for _, tok := range strings.Split(row, " ") {
+		if tok == "" {
+			continue
+		}
+		var width float32 = 1
+		if i := strings.LastIndex(tok, ":"); i > 0 && i < len(tok)-1 {
+			w, err := strconv.ParseFloat(tok[i+1:], 32)
+			if err == nil {
+				if w <= 0 {
+					return nil, fmt.Errorf("invalid key width in '%s'. Must be greater than 0", tok)
+				}
+				width = float32(w)
+				tok = tok[:i]
+			}
+		}
+		sk, ok := vkbdSpecialKeys[tok]
+		if ok {
+			k := *sk
+			k.width = width
+			keys = append(keys, &k)
+			continue
+		}
+		if strings.HasPrefix(tok, "{") && strings.HasSuffix(tok, "}") && len(tok) > 2 {
+			return nil, fmt.Errorf("invalid special key '%s'", tok)
+		}
+		r := []rune(tok)
+		if len(r) != 1 {
+			return nil, fmt.Errorf("invalid key '%s'. Must be a single character or a special key", tok)
+		}
+		keys = append(keys, &sdl_VKey{label: tok, text: tok, code: int(r[0]), action: VKBD_ACTION_CHAR, width: width})
+	}
+	return keys, nil
+}
+
+/****************************************************************************************
+* SDL_VirtualKeyboard code
+* Implements SDL_Widget cos it is one!
+* An on screen keyboard. Keys are passed to the focused widget KeyPress as if they were typed.
+* Characters are passed as KeyPress(char, false, true).
+* Control keys are passed as KeyPress(code, true, true) followed by KeyPress(code, true, false).
+**/
+type SDL_VirtualKeyboard struct {
+	SDL_WidgetBase
+	layers      [VKBD_LAYER_COUNT][][]*sdl_VKey
+	layer       VKBD_LAYER
+	shift, caps bool
+	autoPopUp   bool
+	owner       SDL_Widget
+	getFocused  func() SDL_Widget
+	pressedKey  *sdl_VKey
+	pressedAt   uint64
+}
+
+var _ SDL_Widget = (*SDL_VirtualKeyboard)(nil) // Ensure SDL_VirtualKeyboard 'is a' SDL_Widget
+
+/*
+getFocused should return the widget that will receive the keys. For example SDL_WidgetGroup.GetFocusedWidget.
+If getFocused is nil then keys go to the SDL_Entry that popped the keyboard up. See SetAutoPopUp.
+*/
+func NewSDLVirtualKeyboard(x, y, w, h, id int32, layout *SDL_KeyboardLayout, style STATE_BITS, getFocused func() SDL_Widget) (*SDL_VirtualKeyboard, error) {
+	kb := &SDL_VirtualKeyboard{layer: VKBD_LAYER_LOWER, getFocused: getFocused}
+	kb.SDL_WidgetBase = initBase(x, y, w, h, id, kb, 0, false, style, nil)
+	if layout == nil {
+		layout = NewDefaultKeyboardLayout()
+	}
+	err := kb.SetLayout(layout)
+	if err != nil {
+		return nil, err
+	}
+	return kb, nil
+}
+
+func (kb *SDL_VirtualKeyboard) SetLayout(layout *SDL_KeyboardLayout) error {
+	var layers [VKBD_LAYER_COUNT][][]*sdl_VKey
+	for i, rows := range layout.Layers {
+		if len(rows) == 0 {
+			return fmt.Errorf("keyboard layout layer %d has no rows", i)
+		}
+		layers[i] = make([][]*sdl_VKey, 0)
+		for _, row := range rows {
+			keys, err := parseVKeyRow(row)
+			if err != nil {
+				return fmt.Errorf("keyboard layout layer %d: %s", i, err.Error())
+			}
+			layers[i] = append(layers[i], keys)
+		}
+	}
+	kb.layers = layers
+	return nil
+}
+
+func (kb *SDL_VirtualKeyboard) GetLayer() VKBD_LAYER {
+	return kb.layer
+}
+
+func (kb *SDL_VirtualKeyboard) SetLayer(l VKBD_LAYER) {
+	if int(l) >= 0 && int(l) < VKBD_LAYER_COUNT {
+		kb.layer = l
+		kb.shift = false
+		kb.caps = false
+	}
+}
+
+/*
+If true the keyboard is hidden until an SDL_Entry gains focus and is hidden again when it loses focus.
+If an SDL_Entry already has the focus when it is turned on the keyboard pops up for it.
+Only one keyboard can pop up automatically.
+*/
+func (kb *SDL_VirtualKeyboard) SetAutoPopUp(on bool) {
+	kb.autoPopUp = on
+	if on {
+		GetResourceInstance().SetVirtualKeyboard(kb)
+		kb.SetVisible(false)
+		// The entry with text input started is the focused, editable one
+		if w := GetResourceInstance().textInputOwner; w != nil {
+			kb.focusChanged(w, true)
+		}
+	} else {
+		if GetResourceInstance().GetVirtualKeyboard() == kb {
+			GetResourceInstance().SetVirtualKeyboard(nil)
+		}
+		kb.owner = nil
+	}
+}
+
+func (kb *SDL_VirtualKeyboard) IsAutoPopUp() bool {
+	return kb.autoPopUp
+}
+
+/*
+Called by the focusable widgets when they gain or lose focus.
+*/
+func (kb *SDL_VirtualKeyboard) focusChanged(w SDL_Widget, focus bool) {
+	if !kb.autoPopUp {
+		return
+	}
+	if focus {
+		kb.owner = w
+		kb.SetVisible(true)
+	} else {
+		if kb.owner == w {
+			kb.owner = nil
+			kb.SetVisible(false)
+		}
+	}
+}
+
+func (kb *SDL_VirtualKeyboard) target() SDL_Widget {
+	if kb.getFocused != nil {
+		w := kb.getFocused()
+		if w != nil {
+			return w
+		}
+	}
+	return kb.owner
+}
+
+/*
+Keys are pressed by the left button (or a tap). Right and middle clicks, and a long press, do nothing
+*/
+func (kb *SDL_VirtualKeyboard) Click(md *SDL_MouseData) bool {
+	if kb.IsEnabled() && kb.IsVisible() && !md.IsDragging() && md.GetButtons() == sdl.BUTTON_LEFT {
+		k := kb.keyAt(md.GetX(), md.GetY())
+		if k != nil {
+			kb.pressedKey = k
+			kb.pressedAt = sdl.GetTicks64()
+			kb.press(k)
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Press a key as if it had been tapped
+*/
+func (kb *SDL_VirtualKeyboard) press(k *sdl_VKey) {
+	switch k.action {
+	case VKBD_ACTION_CHAR:
+		t := kb.target()
+		if t != nil {
+			t.KeyPress(k.code, false, true)
+		}
+		if kb.shift {
+			kb.shift = false
+			if !kb.caps {
+				kb.layer = VKBD_LAYER_LOWER
+			}
+		}
+	case VKBD_ACTION_CTRL:
+		t := kb.target()
+		if t != nil {
+			t.KeyPress(k.code, true, true)
+			t.KeyPress(k.code, true, false)
+		}
+	case VKBD_ACTION_SHIFT:
+		kb.shift = !kb.shift
+		kb.layer = kb.lettersLayer()
+	case VKBD_ACTION_CAPS:
+		kb.caps = !kb.caps
+		kb.shift = false
+		kb.layer = kb.lettersLayer()
+	case VKBD_ACTION_SYMBOL:
+		kb.shift = false
+		kb.layer = VKBD_LAYER_SYMBOL
+	case VKBD_ACTION_LETTERS:
+		kb.layer = kb.lettersLayer()
+	case VKBD_ACTION_HIDE:
+		kb.SetVisible(false)
+	}
+}
+
+func (kb *SDL_VirtualKeyboard) lettersLayer() VKBD_LAYER {
+	if kb.shift != kb.caps {
+		return VKBD_LAYER_UPPER
+	}
+	return VKBD_LAYER_LOWER
+}
+
+/*
+Call f for each key in the current layer with the keys screen rectangle.
+Stop if f returns true.
+*/
+func (kb *SDL_VirtualKeyboard) eachKey(f func(*sdl_VKey, *sdl.Rect) bool) {
+	rows := kb.layers[kb.layer]
+	if len(rows) == 0 {
+		return
+	}
+	rh := kb.h / int32(len(rows))
+	y := kb.y
+	for _, row := range rows {
+		var units float32 = 0
+		for _, k := range row {
+			units = units + k.width
+		}
+		if units > 0 {
+			x := float32(kb.x)
+			uw := float32(kb.w) / units
+			for _, k := range row {
+				kw := k.width * uw
+				if f(k, &sdl.Rect{X: int32(x), Y: y, W: int32(kw), H: rh}) {
+					return
+				}
+				x = x + kw
+			}
+		}
+		y = y + rh
+	}
+}
+
+func (kb *SDL_VirtualKeyboard) keyAt(x, y int32) *sdl_VKey {
+	var found *sdl_VKey
+	kb.eachKey(func(k *sdl_VKey, r *sdl.Rect) bool {
+		if isInsideRect(x, y, r) {
+			found = k
+			return true
+		}
+		return false
+	})
+	return found
+}
+
+func (kb *SDL_VirtualKeyboard) isKeyActive(k *sdl_VKey) bool {
+	switch k.action {
+	case VKBD_ACTION_SHIFT:
+		return kb.shift
+	case VKBD_ACTION_CAPS:
+		return kb.caps
+	}
+	return k == kb.pressedKey && (sdl.GetTicks64()-kb.pressedAt) < vkbd_PRESSED_MS
+}
+
+func (kb *SDL_VirtualKeyboard) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
+	if kb.IsVisible() {
+		if kb.ShouldDrawBackground() {
+			bc := kb.GetBackground()
+			renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
+			renderer.FillRect(&sdl.Rect{X: kb.x, Y: kb.y, W: kb.w, H: kb.h})
+		}
+		fg := kb.GetForeground()
+		bc := kb.GetBorderColour()
+		sc := GetResourceInstance().GetCursorSelectColour()
+		var err error
+		kb.eachKey(func(k *sdl_VKey, r *sdl.Rect) bool {
+			kr := widgetShrinkRect(r, 2)
+			if kb.isKeyActive(k) {
+				renderer.SetDrawColor(sc.R, sc.G, sc.B, sc.A)
+				renderer.FillRect(kr)
+			}
+			if kb.ShouldDrawBorder() {
+				renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
+				renderer.DrawRect(kr)
+			}
+			cacheKey := fmt.Sprintf("%s.vkbd.%s.%t", TEXTURE_CACHE_TEXT_PREF, k.label, kb.IsEnabled())
+			ctwe, e := GetResourceInstance().UpdateTextureFromString(renderer, cacheKey, k.label, font, fg)
+			if e != nil {
+				err = e
+				return true
+			}
+			th := kr.H - (kr.H / 3)
+			tw := int32(float32(ctwe.w) * (float32(th) / float32(ctwe.h)))
+			if tw > kr.W-4 {
+				tw = kr.W - 4
+			}
+			renderer.Copy(ctwe.texture, nil, &sdl.Rect{X: kr.X + (kr.W-tw)/2, Y: kr.Y + (kr.H-th)/2, W: tw, H: th})
+			return false
+		})
+		if err != nil {
+			renderer.SetDrawColor(255, 0, 0, 255)
+			renderer.DrawRect(&sdl.Rect{X: kb.x, Y: kb.y, W: kb.w, H: kb.h})
+		}
+	}
+	return nil
+}
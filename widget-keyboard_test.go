@@ -0,0 +1,99 @@
+package go_sdl_widget
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestKeyboardLayoutParse(t *testing.T) {
+	keys, err := parseVKeyRow("a {shift}:2 {space}:5.5 :")
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+	assertInt(t, "Key count", len(keys), 4)
+	assertInt(t, "a code", keys[0].code, 'a')
+	assertBool(t, "Shift", "action", keys[1].action == VKBD_ACTION_SHIFT, true)
+	assertFloat(t, "Space width", float64(keys[2].width), 5.5)
+	assertInt(t, "Colon code", keys[3].code, ':')
+
+	_, err = parseVKeyRow("{nokey}")
+	if err == nil {
+		t.Error("Invalid special key should return an error")
+	}
+	_, err = parseVKeyRow("ab")
+	if err == nil {
+		t.Error("Multi char key should return an error")
+	}
+}
+
+func TestKeyboardPressAndPopUp(t *testing.T) {
+	kb, err := NewSDLVirtualKeyboard(0, 0, 100, 50, 1, nil, WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+	// An entry left focused by another test would pop it up
+	GetResourceInstance().stopTextInput(GetResourceInstance().textInputOwner)
+	kb.SetAutoPopUp(true)
+	defer kb.SetAutoPopUp(false)
+	assertBool(t, "Hidden", "IsVisible", kb.IsVisible(), false)
+
+	e := NewSDLEntry(0, 0, 100, 20, 2, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	assertBool(t, "Popped up", "IsVisible", kb.IsVisible(), true)
+
+	kb.press(vkbdSpecialKeys["{shift}"])
+	assertBool(t, "Shift layer", "Upper", kb.GetLayer() == VKBD_LAYER_UPPER, true)
+	kb.press(&sdl_VKey{text: "A", code: 'A', action: VKBD_ACTION_CHAR})
+	assertBool(t, "Shift released", "Lower", kb.GetLayer() == VKBD_LAYER_LOWER, true)
+	kb.press(&sdl_VKey{text: "b", code: 'b', action: VKBD_ACTION_CHAR})
+	if e.GetText() != "Ab" {
+		t.Errorf("Entry text should be 'Ab' not '%s'", e.GetText())
+	}
+	kb.press(&sdl_VKey{code: sdl.K_BACKSPACE, action: VKBD_ACTION_CTRL})
+	if e.GetText() != "A" {
+		t.Errorf("Entry text should be 'A' not '%s'", e.GetText())
+	}
+
+	e.SetReadOnly(true)
+	assertBool(t, "Read only", "IsVisible", kb.IsVisible(), false)
+	e.SetReadOnly(false)
+	assertBool(t, "Editable", "IsVisible", kb.IsVisible(), true)
+
+	kb.SetAutoPopUp(false)
+	kb.SetVisible(false)
+	kb.SetAutoPopUp(true)
+	assertBool(t, "Turned back on with the entry focused", "IsVisible", kb.IsVisible(), true)
+	kb.press(&sdl_VKey{text: "c", code: 'c', action: VKBD_ACTION_CHAR})
+	assertString(t, "Turned back on", e.GetText(), "Ac")
+
+	e.SetFocused(false)
+	assertBool(t, "Hidden on un focus", "IsVisible", kb.IsVisible(), false)
+	kb.SetAutoPopUp(false)
+	kb.SetAutoPopUp(true)
+	assertBool(t, "Turned back on with nothing focused", "IsVisible", kb.IsVisible(), false)
+}
+
+func TestKeyboardClick(t *testing.T) {
+	e := NewSDLEntry(0, 0, 100, 20, 2, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.SetFocused(true)
+	defer e.SetFocused(false)
+	kb, err := NewSDLVirtualKeyboard(0, 100, 400, 200, 1, nil, WIDGET_STYLE_DRAW_BORDER_AND_BG, func() SDL_Widget { return e })
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+	var x, y int32
+	kb.eachKey(func(k *sdl_VKey, r *sdl.Rect) bool {
+		if k.code == '1' {
+			x, y = r.X+r.W/2, r.Y+r.H/2
+			return true
+		}
+		return false
+	})
+	kb.Click(&SDL_MouseData{x: x, y: y, button: sdl.BUTTON_LEFT, clickCount: 1, down: true})
+	assertString(t, "Left click", e.GetText(), "1")
+	// A long press is sent as a right click
+	kb.Click(&SDL_MouseData{x: x, y: y, button: sdl.BUTTON_RIGHT, clickCount: 1})
+	kb.Click(&SDL_MouseData{x: x, y: y, button: sdl.BUTTON_MIDDLE, clickCount: 1, down: true})
+	assertString(t, "Right and middle click", e.GetText(), "1")
+}
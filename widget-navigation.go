@@ -0,0 +1,150 @@
+package go_sdl_widget
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type FOCUS_DIRECTION int
+
+const (
+	FOCUS_DIRECTION_UP FOCUS_DIRECTION = iota
+	FOCUS_DIRECTION_DOWN
+	FOCUS_DIRECTION_LEFT
+	FOCUS_DIRECTION_RIGHT
+
+	navigate_PERP_WEIGHT int32 = 3 // Distance across the direction counts this much more than distance along it
+)
+
+var arrowKeyDirections = map[int]FOCUS_DIRECTION{
+	sdl.K_UP:    FOCUS_DIRECTION_UP,
+	sdl.K_DOWN:  FOCUS_DIRECTION_DOWN,
+	sdl.K_LEFT:  FOCUS_DIRECTION_LEFT,
+	sdl.K_RIGHT: FOCUS_DIRECTION_RIGHT,
+}
+
+var dPadDirections = map[uint8]FOCUS_DIRECTION{
+	sdl.CONTROLLER_BUTTON_DPAD_UP:    FOCUS_DIRECTION_UP,
+	sdl.CONTROLLER_BUTTON_DPAD_DOWN:  FOCUS_DIRECTION_DOWN,
+	sdl.CONTROLLER_BUTTON_DPAD_LEFT:  FOCUS_DIRECTION_LEFT,
+	sdl.CONTROLLER_BUTTON_DPAD_RIGHT: FOCUS_DIRECTION_RIGHT,
+}
+
+/*
+Called when B on a game controller, or Escape, is not used by anything else. Return true if it was used.
+Use it to close a dialog or go back a screen.
+*/
+func (wg *SDL_WidgetGroup) SetOnCancel(f func() bool) {
+	wg.onCancel = f
+}
+
+/*
+Move the focus to the nearest focusable widget in the direction d from the focused widget, using GetRect.
+Widgets wholly past the edge of the focused widget are preferred. Those in line with it are preferred over
+those off to the side. Returns the newly focused widget, or nil if nothing is focused or there is nothing
+in that direction. The focus does not wrap.
+*/
+func (wg *SDL_WidgetGroup) FocusDirection(d FOCUS_DIRECTION) SDL_Widget {
+	focused := wg.GetFocusedWidget()
+	if focused == nil {
+		return nil
+	}
+	f := focused.GetRect()
+	var best SDL_Widget
+	var bestBeyond bool
+	var bestScore int32
+	for _, w := range wg.focusableWidgets() {
+		if w == focused {
+			continue
+		}
+		along, perp, centre := navigateDistance(f, w.GetRect(), d)
+		beyond := along >= 0
+		if !beyond && !centre {
+			continue
+		}
+		score := max32(along, 0) + perp*navigate_PERP_WEIGHT
+		if best == nil || beyond && !bestBeyond || beyond == bestBeyond && score < bestScore {
+			best, bestBeyond, bestScore = w, beyond, score
+		}
+	}
+	if best != nil {
+		wg.moveFocus(best)
+	}
+	return best
+}
+
+/*
+along is the gap from the edge of f to the near edge of r in direction d. Negative if they overlap.
+perp is the gap between them across the direction. 0 if they are in line.
+centre is true if the centre of r is past the centre of f in direction d.
+*/
+func navigateDistance(f, r *sdl.Rect, d FOCUS_DIRECTION) (along, perp int32, centre bool) {
+	switch d {
+	case FOCUS_DIRECTION_UP:
+		along = f.Y - (r.Y + r.H)
+		centre = r.Y*2+r.H < f.Y*2+f.H
+	case FOCUS_DIRECTION_DOWN:
+		along = r.Y - (f.Y + f.H)
+		centre = r.Y*2+r.H > f.Y*2+f.H
+	case FOCUS_DIRECTION_LEFT:
+		along = f.X - (r.X + r.W)
+		centre = r.X*2+r.W < f.X*2+f.W
+	case FOCUS_DIRECTION_RIGHT:
+		along = r.X - (f.X + f.W)
+		centre = r.X*2+r.W > f.X*2+f.W
+	}
+	if d == FOCUS_DIRECTION_UP || d == FOCUS_DIRECTION_DOWN {
+		perp = max32(max32(r.X-(f.X+f.W), f.X-(r.X+r.W)), 0)
+	} else {
+		perp = max32(max32(r.Y-(f.Y+f.H), f.Y-(r.Y+r.H)), 0)
+	}
+	return along, perp, centre
+}
+
+/*
+The D-pad moves the focus (see FocusDirection). If nothing is focused it focuses the first widget in the tab order.
+A clicks the focused widget as Return does. B cancels, see cancel.
+*/
+func (wg *SDL_WidgetGroup) controllerButton(e *sdl.ControllerButtonEvent) bool {
+	if e.State != sdl.PRESSED {
+		return false
+	}
+	if d, ok := dPadDirections[e.Button]; ok {
+		if wg.GetFocusedWidget() == nil {
+			return wg.FocusNext() != nil
+		}
+		return wg.FocusDirection(d) != nil
+	}
+	switch e.Button {
+	case sdl.CONTROLLER_BUTTON_A:
+		return wg.KeyPress(sdl.K_RETURN, true, true)
+	case sdl.CONTROLLER_BUTTON_B:
+		return wg.cancel()
+	}
+	return false
+}
+
+/*
+Stop a drag or press if there is one. Otherwise the focused widget is sent Escape (an entry closes its popup).
+If it does not use it the function given to SetOnCancel is called.
+*/
+func (wg *SDL_WidgetGroup) cancel() bool {
+	if wg.drag != nil {
+		wg.CancelDrag()
+		return true
+	}
+	if wg.mouseDown != nil {
+		wg.cancelPress()
+		return true
+	}
+	if wg.KeyPress(sdl.K_ESCAPE, true, true) {
+		return true
+	}
+	return wg.onCancel != nil && wg.onCancel()
+}
+
+func max32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,151 @@
+package go_sdl_widget
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func padButton(b uint8, down bool) *sdl.ControllerButtonEvent {
+	e := &sdl.ControllerButtonEvent{Type: sdl.CONTROLLERBUTTONUP, Button: b, State: sdl.RELEASED}
+	if down {
+		e.Type = sdl.CONTROLLERBUTTONDOWN
+		e.State = sdl.PRESSED
+	}
+	return e
+}
+
+func focusedId(wg *SDL_WidgetGroup) int32 {
+	if f := wg.GetFocusedWidget(); f != nil {
+		return f.GetWidgetId()
+	}
+	return 0
+}
+
+/*
+Three buttons (ids 2, 3, 4) in a row. Below them an entry (5) on the left and a knob (6) on the right.
+Below those a button (7) in the middle
+*/
+func navigationGroup(onClick func(string, int32, int32, int32) bool) (*SDL_WidgetGroup, *SDL_WidgetSubGroup) {
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 400, 200, 1, WIDGET_STYLE_DRAW_NONE)
+	sg.Add(NewSDLButton(10, 10, 50, 24, 2, "B1", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick))
+	sg.Add(NewSDLButton(100, 10, 50, 24, 3, "B2", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick))
+	sg.Add(NewSDLButton(190, 10, 50, 24, 4, "B3", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick))
+	sg.Add(NewSDLEntry(10, 60, 100, 24, 5, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil))
+	sg.Add(NewSDLKnob(190, 60, 50, 50, 6, 0, 100, 50, WIDGET_STYLE_DRAW_BORDER_AND_BG, nil))
+	sg.Add(NewSDLButton(100, 150, 50, 24, 7, "B4", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick))
+	return wg, sg
+}
+
+func TestFocusDirection(t *testing.T) {
+	wg, sg := navigationGroup(nil)
+	assertBool(t, "Nothing focused", "moved", wg.FocusDirection(FOCUS_DIRECTION_RIGHT) != nil, false)
+	wg.SetFocusedId(2)
+	for _, c := range []struct {
+		d        FOCUS_DIRECTION
+		expected int32
+	}{
+		{FOCUS_DIRECTION_RIGHT, 3},
+		{FOCUS_DIRECTION_DOWN, 5},  // In line below, over the knob which is off to the side
+		{FOCUS_DIRECTION_RIGHT, 6}, // In line with the entry, not b2 which is up and to the right
+		{FOCUS_DIRECTION_UP, 4},
+		{FOCUS_DIRECTION_LEFT, 3},
+		{FOCUS_DIRECTION_LEFT, 2},
+		{FOCUS_DIRECTION_LEFT, 2}, // Does not wrap
+		{FOCUS_DIRECTION_DOWN, 5},
+		{FOCUS_DIRECTION_DOWN, 7},
+		{FOCUS_DIRECTION_UP, 5},
+	} {
+		wg.FocusDirection(c.d)
+		assertInt(t, "FocusDirection", int(focusedId(wg)), int(c.expected))
+	}
+
+	// Disabled widgets are skipped
+	wg.SetFocusedId(6)
+	sg.GetWidgetWithId(5).SetEnabled(false)
+	wg.FocusDirection(FOCUS_DIRECTION_LEFT)
+	assertInt(t, "Skip disabled", int(focusedId(wg)), 3)
+}
+
+func TestArrowKeyNavigation(t *testing.T) {
+	wg, sg := navigationGroup(nil)
+	k := sg.GetWidgetWithId(6).(*SDL_Knob)
+	wg.SetFocusedId(2)
+	assertBool(t, "Right", "used", wg.HandleEvent(keyEvent(sdl.K_RIGHT, 0, true)), true)
+	assertInt(t, "Right", int(focusedId(wg)), 3)
+	wg.HandleEvent(keyEvent(sdl.K_RIGHT, 0, false))
+	assertInt(t, "Key up", int(focusedId(wg)), 3)
+	// Shift+arrow does not move the focus
+	assertBool(t, "Shift+Down", "used", wg.HandleEvent(keyEvent(sdl.K_DOWN, sdl.KMOD_LSHIFT, true)), false)
+	assertInt(t, "Shift+Down", int(focusedId(wg)), 3)
+	wg.HandleEvent(keyEvent(sdl.K_DOWN, 0, true))
+	assertInt(t, "Down", int(focusedId(wg)), 5)
+	// The entry uses the arrows to move the cursor
+	wg.HandleEvent(keyEvent(sdl.K_RIGHT, 0, true))
+	assertInt(t, "Right in entry", int(focusedId(wg)), 5)
+	// The knob uses them to turn
+	wg.SetFocusedId(6)
+	wg.HandleEvent(keyEvent(sdl.K_UP, 0, true))
+	assertInt(t, "Up on knob", int(focusedId(wg)), 6)
+	assertFloat(t, "Up on knob", k.GetValue(), 51)
+	// Nothing below b4
+	wg.SetFocusedId(7)
+	assertBool(t, "Down from b4", "used", wg.HandleEvent(keyEvent(sdl.K_DOWN, 0, true)), false)
+}
+
+func TestControllerNavigation(t *testing.T) {
+	clicks := ""
+	wg, sg := navigationGroup(func(s string, id, x, y int32) bool {
+		clicks = clicks + s + " "
+		return true
+	})
+
+	// The D-pad focuses the first widget when nothing is focused
+	assertBool(t, "D-pad", "used", wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_DPAD_DOWN, true)), true)
+	assertInt(t, "D-pad first", int(focusedId(wg)), 2)
+	wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_DPAD_DOWN, false))
+	assertInt(t, "D-pad up", int(focusedId(wg)), 2)
+	wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_DPAD_DOWN, true))
+	assertInt(t, "D-pad down", int(focusedId(wg)), 5)
+	// Not passed to the entry or the knob
+	wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_DPAD_RIGHT, true))
+	assertInt(t, "D-pad right from entry", int(focusedId(wg)), 6)
+	wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_DPAD_UP, true))
+	assertInt(t, "D-pad up from knob", int(focusedId(wg)), 4)
+	assertFloat(t, "D-pad knob", sg.GetWidgetWithId(6).(*SDL_Knob).GetValue(), 50)
+
+	// A clicks
+	assertBool(t, "A", "used", wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_A, true)), true)
+	wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_A, false))
+	assertString(t, "A", clicks, "B3 ")
+
+	// B with nothing to cancel
+	assertBool(t, "B", "used", wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_B, true)), false)
+	cancelled := 0
+	wg.SetOnCancel(func() bool {
+		cancelled++
+		return true
+	})
+	assertBool(t, "B", "used", wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_B, true)), true)
+	assertInt(t, "B", cancelled, 1)
+	wg.HandleEvent(keyEvent(sdl.K_ESCAPE, 0, true))
+	assertInt(t, "Escape", cancelled, 2)
+
+	// B cancels a press without clicking
+	clicks = ""
+	wg.HandleEvent(mouseDown(20, 20))
+	assertBool(t, "B on press", "used", wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_B, true)), true)
+	wg.HandleEvent(mouseUp(20, 20))
+	assertString(t, "B on press", clicks, "")
+	assertInt(t, "B on press", cancelled, 2)
+
+	// B cancels a drag
+	sg.GetWidgetWithId(2).(*SDL_Button).SetDragSource(func(x, y int32) *SDL_DragPayload { return NewTextPayload("B1") })
+	wg.HandleEvent(mouseDown(20, 20))
+	wg.HandleEvent(mouseMove(40, 80))
+	assertBool(t, "Drag", "started", wg.GetDragPayload() != nil, true)
+	wg.HandleEvent(padButton(sdl.CONTROLLER_BUTTON_B, true))
+	assertBool(t, "B on drag", "dragging", wg.GetDragPayload() != nil, false)
+	assertInt(t, "B on drag", cancelled, 2)
+}
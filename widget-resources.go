@@ -27,8 +27,13 @@ type sdl_Resources struct {
 	cursorInsertColour *sdl.Color
 	cursorAppendColour *sdl.Color
 	cursorSelectColour *sdl.Color
+	focusRingColour    *sdl.Color
+	dropTargetColour   *sdl.Color
 	selectCharsFwd     []byte
 	selectCharsRev     []byte
+	virtualKeyboard    *SDL_VirtualKeyboard
+	textInputOwner     SDL_Widget
+	clipboard          SDL_Clipboard
 }
 
 type STATE_COLOUR uint
@@ -39,7 +44,8 @@ const (
 	WIDGET_COLOUR_INDEX_DISABLE STATE_COLOUR = 1
 	WIDGET_COLOUR_INDEX_FOCUS   STATE_COLOUR = 2
 	WIDGET_COLOUR_INDEX_ERROR   STATE_COLOUR = 3
-	WIDGET_COLOUR_INDEX_SIZE    int          = 4 // So we create an array the right size
+	WIDGET_COLOUR_INDEX_HOVER   STATE_COLOUR = 4
+	WIDGET_COLOUR_INDEX_SIZE    int          = 5 // So we create an array the right size
 	WIDGET_CURSOR_STATE         int          = 100
 	WIDGET_SELECT_STATE         int          = 101
 
@@ -62,6 +68,7 @@ var (
 		"disabled": WIDGET_COLOUR_INDEX_DISABLE,
 		"focused":  WIDGET_COLOUR_INDEX_FOCUS,
 		"error":    WIDGET_COLOUR_INDEX_ERROR,
+		"hover":    WIDGET_COLOUR_INDEX_HOVER,
 	}
 	configMapStyle = map[string]STYLE_COLOUR{
 		"fg":     WIDGET_COLOUR_STYLE_FG,
@@ -103,6 +110,10 @@ func GetResourceInstance() *sdl_Resources {
 			sdlResourceInstance.colours[WIDGET_COLOUR_INDEX_ERROR][WIDGET_COLOUR_STYLE_BG] = &sdl.Color{R: 100, G: 0, B: 0, A: 255}
 			sdlResourceInstance.colours[WIDGET_COLOUR_INDEX_ERROR][WIDGET_COLOUR_STYLE_BORDER] = &sdl.Color{R: 255, G: 0, B: 0, A: 255}
 
+			sdlResourceInstance.colours[WIDGET_COLOUR_INDEX_HOVER][WIDGET_COLOUR_STYLE_FG] = &sdl.Color{R: 150, G: 255, B: 150, A: 255}
+			sdlResourceInstance.colours[WIDGET_COLOUR_INDEX_HOVER][WIDGET_COLOUR_STYLE_BG] = &sdl.Color{R: 0, G: 140, B: 0, A: 255}
+			sdlResourceInstance.colours[WIDGET_COLOUR_INDEX_HOVER][WIDGET_COLOUR_STYLE_BORDER] = &sdl.Color{R: 150, G: 255, B: 150, A: 255}
+
 			sdlResourceInstance.cursorInsertColour = &sdl.Color{R: 255, G: 255, B: 255, A: 255}
 			sdlResourceInstance.cursorAppendColour = &sdl.Color{R: 255, G: 0, B: 255, A: 255}
 			sdlResourceInstance.cursorSelectColour = &sdl.Color{R: 100, G: 0, B: 100, A: 255}
@@ -225,6 +236,30 @@ func (r *sdl_Resources) setConfigValue(n []string, v string) error {
 			default:
 				return fmt.Errorf("invalid name. Expecting 'cursor.insert, cursor.append, cursor.select' Found '%s'", n)
 			}
+		case "focus":
+			c, err := parseColourString(v)
+			if err != nil {
+				return err
+			}
+			switch n1 {
+			case "ring":
+				r.SetFocusRingColour(c)
+				return nil
+			default:
+				return fmt.Errorf("invalid name. Expecting 'focus.ring' Found '%s'", n)
+			}
+		case "drop":
+			c, err := parseColourString(v)
+			if err != nil {
+				return err
+			}
+			switch n1 {
+			case "target":
+				r.SetDropTargetColour(c)
+				return nil
+			default:
+				return fmt.Errorf("invalid name. Expecting 'drop.target' Found '%s'", n)
+			}
 		case "select":
 			if len(v) < 1 {
 				return fmt.Errorf("invalid value. Expecting string longer than 1 char")
@@ -240,7 +275,7 @@ func (r *sdl_Resources) setConfigValue(n []string, v string) error {
 				return fmt.Errorf("invalid name. Expecting 'select.forward, select.backward' Found '%s'", n)
 			}
 		default:
-			return fmt.Errorf("invalid name. Expecting a name from %v or 'cursor or select' Found '%s'", configMapState, n0)
+			return fmt.Errorf("invalid name. Expecting a name from %v or 'cursor, drop, focus or select' Found '%s'", configMapState, n0)
 		}
 	}
 	i2, ok := configMapStyle[n1]
@@ -331,6 +366,34 @@ func (r *sdl_Resources) GetCursorSelectColour() *sdl.Color {
 	return r.cursorSelectColour
 }
 
+/*
+The ring drawn around a focused button, image or knob. Defaults to the focused border colour
+*/
+func (r *sdl_Resources) GetFocusRingColour() *sdl.Color {
+	if r.focusRingColour == nil {
+		return r.GetColour(WIDGET_COLOUR_INDEX_FOCUS, WIDGET_COLOUR_STYLE_BORDER)
+	}
+	return r.focusRingColour
+}
+
+func (r *sdl_Resources) SetFocusRingColour(c *sdl.Color) {
+	r.focusRingColour = c
+}
+
+/*
+The highlight drawn around a widget that will accept the payload being dragged. Defaults to the hover border colour
+*/
+func (r *sdl_Resources) GetDropTargetColour() *sdl.Color {
+	if r.dropTargetColour == nil {
+		return r.GetColour(WIDGET_COLOUR_INDEX_HOVER, WIDGET_COLOUR_STYLE_BORDER)
+	}
+	return r.dropTargetColour
+}
+
+func (r *sdl_Resources) SetDropTargetColour(c *sdl.Color) {
+	r.dropTargetColour = c
+}
+
 func (r *sdl_Resources) SetCursorInsertColour(c *sdl.Color) {
 	r.cursorInsertColour = c
 }
@@ -420,6 +483,54 @@ func (b *sdl_Resources) GetSelectCharsRev() string {
 	return string(b.selectCharsRev)
 }
 
+func (r *sdl_Resources) SetVirtualKeyboard(kb *SDL_VirtualKeyboard) {
+	r.virtualKeyboard = kb
+}
+
+func (r *sdl_Resources) GetVirtualKeyboard() *SDL_VirtualKeyboard {
+	return r.virtualKeyboard
+}
+
+/*
+Set the clipboard used by the widgets. nil restores the default SDL clipboard.
+See NewMemoryClipboard and NewHistoryClipboard.
+*/
+func (r *sdl_Resources) SetClipboard(c SDL_Clipboard) {
+	r.clipboard = c
+}
+
+func (r *sdl_Resources) GetClipboard() SDL_Clipboard {
+	if r.clipboard == nil {
+		return &sdl_SystemClipboard{}
+	}
+	return r.clipboard
+}
+
+/*
+Start SDL text input (and the IME) for a widget that has gained focus
+*/
+func (r *sdl_Resources) startTextInput(w SDL_Widget) {
+	r.textInputOwner = w
+	sdl.StartTextInput()
+}
+
+/*
+Stop SDL text input if the widget losing focus is the one that started it
+*/
+func (r *sdl_Resources) stopTextInput(w SDL_Widget) {
+	if r.textInputOwner == w {
+		r.textInputOwner = nil
+		sdl.StopTextInput()
+	}
+}
+
+/*
+True if a widget has started SDL text input. Printable keys then arrive as sdl.TextInputEvent
+*/
+func (r *sdl_Resources) isTextInputActive() bool {
+	return r.textInputOwner != nil
+}
+
 func (r *sdl_Resources) GetTextureCache() *SDL_TextureCache {
 	return r.textureCache
 }
@@ -462,14 +573,14 @@ func (r *sdl_Resources) AddTexturesFromFileMap(renderer *sdl.Renderer, fileNames
 }
 
 func (r *sdl_Resources) GetTextureListFromCachedRunes(text string, colour *sdl.Color) []*SDL_TextureCacheEntry {
-	list := make([]*SDL_TextureCacheEntry, len(text))
+	list := make([]*SDL_TextureCacheEntry, 0, len(text))
 	cid := GetColourId(colour)
-	for i, c := range text {
+	for _, c := range text {
 		ec := r.textureCache.textureMap[fmt.Sprintf("|%c%d", c, cid)]
 		if ec == nil {
 			return nil
 		}
-		list[i] = ec
+		list = append(list, ec)
 	}
 	return list
 }
@@ -481,7 +592,8 @@ func (r *sdl_Resources) GetScaledTextureListFromCachedRunesLinked(text string, c
 	var sw float32 = 0
 	ofs := float32(offset)
 	cid := GetColourId(colour)
-	for i, c := range text {
+	i := 0
+	for _, c := range text {
 		tce := r.textureCache.textureMap[fmt.Sprintf("|%c%d", c, cid)]
 		if tce == nil {
 			return rootEnt
@@ -496,6 +608,7 @@ func (r *sdl_Resources) GetScaledTextureListFromCachedRunesLinked(text string, c
 			currentEnt = nextEnt
 		}
 		ofs = ofs + sw
+		i++
 	}
 	return rootEnt
 }
@@ -0,0 +1,384 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+type SHORTCUT_MOD uint16
+
+const (
+	SHORTCUT_MOD_CTRL SHORTCUT_MOD = 1 << iota
+	SHORTCUT_MOD_ALT
+	SHORTCUT_MOD_SHIFT
+	SHORTCUT_MOD_GUI
+)
+
+/*
+Named keys in a chord. Any other key is a single character. Names are not case sensitive.
+*/
+var shortcutKeyNames = map[string]int{
+	"backspace": sdl.K_BACKSPACE,
+	"tab":       sdl.K_TAB,
+	"enter":     sdl.K_RETURN,
+	"return":    sdl.K_RETURN,
+	"esc":       sdl.K_ESCAPE,
+	"escape":    sdl.K_ESCAPE,
+	"space":     sdl.K_SPACE,
+	"delete":    sdl.K_DELETE,
+	"del":       sdl.K_DELETE,
+	"insert":    sdl.K_INSERT,
+	"ins":       sdl.K_INSERT,
+	"home":      sdl.K_HOME,
+	"end":       sdl.K_END,
+	"pageup":    sdl.K_PAGEUP,
+	"pagedown":  sdl.K_PAGEDOWN,
+	"left":      sdl.K_LEFT,
+	"right":     sdl.K_RIGHT,
+	"up":        sdl.K_UP,
+	"down":      sdl.K_DOWN,
+	"plus":      '+',
+}
+
+/*
+The name shown by SDL_KeyChord.String() for each named key
+*/
+var shortcutKeyDisplay = map[int]string{
+	sdl.K_BACKSPACE: "Backspace",
+	sdl.K_TAB:       "Tab",
+	sdl.K_RETURN:    "Enter",
+	sdl.K_ESCAPE:    "Esc",
+	sdl.K_SPACE:     "Space",
+	sdl.K_DELETE:    "Delete",
+	sdl.K_INSERT:    "Insert",
+	sdl.K_HOME:      "Home",
+	sdl.K_END:       "End",
+	sdl.K_PAGEUP:    "PageUp",
+	sdl.K_PAGEDOWN:  "PageDown",
+	sdl.K_LEFT:      "Left",
+	sdl.K_RIGHT:     "Right",
+	sdl.K_UP:        "Up",
+	sdl.K_DOWN:      "Down",
+	'+':             "Plus",
+}
+
+var shortcutModNames = map[string]SHORTCUT_MOD{
+	"ctrl":    SHORTCUT_MOD_CTRL,
+	"control": SHORTCUT_MOD_CTRL,
+	"alt":     SHORTCUT_MOD_ALT,
+	"shift":   SHORTCUT_MOD_SHIFT,
+	"gui":     SHORTCUT_MOD_GUI,
+	"cmd":     SHORTCUT_MOD_GUI,
+	"super":   SHORTCUT_MOD_GUI,
+	"meta":    SHORTCUT_MOD_GUI,
+}
+
+/****************************************************************************************
+* SDL_KeyChord code
+* A key and the modifiers held with it. Left and right modifier keys are the same.
+* Letters are held lower case as that is the sdl.Keycode SDL reports with or without Shift.
+**/
+type SDL_KeyChord struct {
+	key int
+	mod SHORTCUT_MOD
+}
+
+/*
+Parse a chord such as "Ctrl+S", "F5" or "Alt+Shift+N". Modifiers are Ctrl, Alt, Shift and Gui (or Cmd).
+The key is a single character, F1 to F12 or a named key like Esc, Enter, Delete, PageUp or Left. Use Plus for '+'
+*/
+func ParseKeyChord(chord string) (SDL_KeyChord, error) {
+	parts := strings.Split(strings.TrimSpace(chord), "+")
+	kc := SDL_KeyChord{}
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		lp := strings.ToLower(p)
+		if i < len(parts)-1 {
+			m, ok := shortcutModNames[lp]
+			if !ok {
+				return kc, fmt.Errorf("invalid modifier '%s' in key chord '%s'", p, chord)
+			}
+			kc.mod = kc.mod | m
+			continue
+		}
+		if p == "" {
+			return kc, fmt.Errorf("key chord '%s' has no key", chord)
+		}
+		if k, ok := shortcutKeyNames[lp]; ok {
+			kc.key = k
+		} else if f, err := strconv.Atoi(strings.TrimPrefix(lp, "f")); err == nil && lp[0] == 'f' && f >= 1 && f <= 12 {
+			kc.key = sdl.K_F1 + f - 1
+		} else if utf8.RuneCountInString(p) == 1 {
+			r, _ := utf8.DecodeRuneInString(p)
+			kc.key = int(unicode.ToLower(r))
+		} else {
+			return kc, fmt.Errorf("invalid key '%s' in key chord '%s'", p, chord)
+		}
+	}
+	return kc, nil
+}
+
+/*
+The chord for a key event. Only Ctrl, Alt, Shift and Gui are used. Num lock and Caps lock are ignored.
+*/
+func NewKeyChord(key int, mod uint16) SDL_KeyChord {
+	kc := SDL_KeyChord{key: key}
+	if mod&sdl.KMOD_CTRL != 0 {
+		kc.mod = kc.mod | SHORTCUT_MOD_CTRL
+	}
+	if mod&sdl.KMOD_ALT != 0 {
+		kc.mod = kc.mod | SHORTCUT_MOD_ALT
+	}
+	if mod&sdl.KMOD_SHIFT != 0 {
+		kc.mod = kc.mod | SHORTCUT_MOD_SHIFT
+	}
+	if mod&sdl.KMOD_GUI != 0 {
+		kc.mod = kc.mod | SHORTCUT_MOD_GUI
+	}
+	return kc
+}
+
+func (kc SDL_KeyChord) GetKey() int {
+	return kc.key
+}
+
+func (kc SDL_KeyChord) GetModifiers() SHORTCUT_MOD {
+	return kc.mod
+}
+
+/*
+The chord in the form accepted by ParseKeyChord. Modifiers are always in the order Ctrl, Alt, Shift, Gui
+*/
+func (kc SDL_KeyChord) String() string {
+	var sb strings.Builder
+	if kc.mod&SHORTCUT_MOD_CTRL != 0 {
+		sb.WriteString("Ctrl+")
+	}
+	if kc.mod&SHORTCUT_MOD_ALT != 0 {
+		sb.WriteString("Alt+")
+	}
+	if kc.mod&SHORTCUT_MOD_SHIFT != 0 {
+		sb.WriteString("Shift+")
+	}
+	if kc.mod&SHORTCUT_MOD_GUI != 0 {
+		sb.WriteString("Gui+")
+	}
+	if n, ok := shortcutKeyDisplay[kc.key]; ok {
+		sb.WriteString(n)
+	} else if kc.key >= sdl.K_F1 && kc.key <= sdl.K_F12 {
+		sb.WriteString(fmt.Sprintf("F%d", kc.key-sdl.K_F1+1))
+	} else {
+		sb.WriteRune(unicode.ToUpper(rune(kc.key)))
+	}
+	return sb.String()
+}
+
+/****************************************************************************************
+* Shortcuts registered with SDL_WidgetGroup.AddShortcut
+**/
+type sdl_Shortcut struct {
+	chord  SDL_KeyChord
+	scope  *SDL_WidgetSubGroup // nil is global
+	action func(SDL_KeyChord) bool
+	handle SDL_ListenerHandle
+}
+
+/*
+Bind a chord to an action. If scope is nil the shortcut is global. Otherwise it is only used
+while the focused widget is in the scope sub group. Scoped shortcuts are tried before global ones.
+The action returns true if it used the key. If it returns false the key is passed to the focused widget.
+Returns an error if the chord is invalid or is already bound in the same scope.
+*/
+func (wg *SDL_WidgetGroup) AddShortcut(chord string, scope *SDL_WidgetSubGroup, action func(SDL_KeyChord) bool) (SDL_ListenerHandle, error) {
+	kc, err := ParseKeyChord(chord)
+	if err != nil {
+		return 0, err
+	}
+	if action == nil {
+		return 0, fmt.Errorf("shortcut '%s' has no action", kc)
+	}
+	for _, s := range wg.shortcuts {
+		if s.chord == kc && s.scope == scope {
+			return 0, fmt.Errorf("shortcut '%s' is already bound%s", kc, scopeName(scope))
+		}
+	}
+	h := SDL_ListenerHandle(atomic.AddUint64(&listenerHandles, 1))
+	wg.shortcuts = append(wg.shortcuts, &sdl_Shortcut{chord: kc, scope: scope, action: action, handle: h})
+	return h, nil
+}
+
+/*
+Remove a shortcut added with AddShortcut. Returns false if it was not found
+*/
+func (wg *SDL_WidgetGroup) RemoveShortcut(h SDL_ListenerHandle) bool {
+	for i, s := range wg.shortcuts {
+		if s.handle == h {
+			wg.shortcuts = append(wg.shortcuts[:i], wg.shortcuts[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Describe the bindings that collide. Mnemonics can change after shortcuts are added so they are checked here.
+Reports visible widgets that share a mnemonic letter and mnemonics hidden by an Alt+letter shortcut.
+Global shortcuts that are overridden by a scoped shortcut are not reported.
+*/
+func (wg *SDL_WidgetGroup) ShortcutConflicts() []string {
+	conflicts := make([]string, 0)
+	seen := make(map[rune]SDL_Widget)
+	for _, w := range wg.mnemonicWidgets() {
+		m := unicode.ToLower(w.GetMnemonic())
+		if o, ok := seen[m]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("mnemonic '%c' is used by widget %d and widget %d", unicode.ToUpper(m), o.GetWidgetId(), w.GetWidgetId()))
+			continue
+		}
+		seen[m] = w
+		for _, s := range wg.shortcuts {
+			if s.chord == (SDL_KeyChord{key: int(m), mod: SHORTCUT_MOD_ALT}) {
+				conflicts = append(conflicts, fmt.Sprintf("mnemonic '%c' of widget %d is hidden by shortcut '%s'%s", unicode.ToUpper(m), w.GetWidgetId(), s.chord, scopeName(s.scope)))
+			}
+		}
+	}
+	return conflicts
+}
+
+func scopeName(scope *SDL_WidgetSubGroup) string {
+	if scope == nil {
+		return ""
+	}
+	return fmt.Sprintf(" in sub group %d", scope.GetWidgetId())
+}
+
+/*
+Called from HandleEvent before the key is passed to the focused widget.
+Shortcuts first, scoped then global. Then Alt+letter for mnemonics.
+*/
+func (wg *SDL_WidgetGroup) shortcut(kc SDL_KeyChord) bool {
+	focused := wg.GetFocusedWidget()
+	for pass := 0; pass < 2; pass++ {
+		for _, s := range wg.shortcuts {
+			if s.chord != kc || (pass == 0) == (s.scope == nil) {
+				continue
+			}
+			if s.scope != nil && !wg.inScope(s.scope, focused) {
+				continue
+			}
+			if s.action(kc) {
+				return true
+			}
+		}
+	}
+	if kc.mod == SHORTCUT_MOD_ALT && kc.key < 0x40000000 {
+		m := unicode.ToLower(rune(kc.key))
+		for _, w := range wg.mnemonicWidgets() {
+			if unicode.ToLower(w.GetMnemonic()) == m {
+				return wg.activateMnemonic(w)
+			}
+		}
+	}
+	return false
+}
+
+/*
+Focus the widget if it can be focused and click it at its centre
+*/
+func (wg *SDL_WidgetGroup) activateMnemonic(w SDL_Widget) bool {
+	used := false
+	if w.CanFocus() {
+		wg.moveFocus(w)
+		used = true
+	}
+	r := w.GetRect()
+	if w.Click(&SDL_MouseData{x: r.X + (r.W / 2), y: r.Y + (r.H / 2), button: sdl.BUTTON_LEFT, clickCount: 1, modifiers: sdl.KMOD_LALT}) {
+		used = true
+	}
+	return used
+}
+
+func (wg *SDL_WidgetGroup) inScope(scope *SDL_WidgetSubGroup, focused SDL_Widget) bool {
+	if focused == nil || !scope.IsVisible() || !scope.IsEnabled() {
+		return false
+	}
+	for _, w := range appendFocusable(make([]SDL_Widget, 0), scope.ListWidgets()) {
+		if w == focused {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+All enabled and visible widgets with a mnemonic, in the order they were added. Includes widgets in containers
+*/
+func (wg *SDL_WidgetGroup) mnemonicWidgets() []SDL_Widget {
+	l := make([]SDL_Widget, 0)
+	for _, wl := range wg.wigetLists {
+		if wl.IsEnabled() && wl.IsVisible() {
+			l = appendMnemonic(l, wl.ListWidgets())
+		}
+	}
+	return l
+}
+
+func appendMnemonic(l []SDL_Widget, widgets []SDL_Widget) []SDL_Widget {
+	for _, w := range widgets {
+		if !w.IsEnabled() || !w.IsVisible() {
+			continue
+		}
+		if wc, isContainer := w.(SDL_Container); isContainer {
+			l = appendMnemonic(l, wc.ListWidgets())
+		} else if w.GetMnemonic() != 0 {
+			l = append(l, w)
+		}
+	}
+	return l
+}
+
+/*
+Byte index of the first letter in text that matches the mnemonic, ignoring case. -1 if not found
+*/
+func mnemonicIndex(text string, m rune) int {
+	if m == 0 {
+		return -1
+	}
+	m = unicode.ToLower(m)
+	for i, r := range text {
+		if unicode.ToLower(r) == m {
+			return i
+		}
+	}
+	return -1
+}
+
+/*
+Underline the mnemonic letter in text drawn at x,y with height h.
+scale converts the font width of the text to the width it is drawn at.
+*/
+func (b *SDL_WidgetBase) drawMnemonic(renderer *sdl.Renderer, font *ttf.Font, text string, x, y, h int32, scale float32) {
+	i := mnemonicIndex(text, b.mnemonic)
+	if i < 0 || font == nil {
+		return
+	}
+	_, n := utf8.DecodeRuneInString(text[i:])
+	pw, _, err := font.SizeUTF8(text[:i])
+	if err != nil {
+		return
+	}
+	cw, _, err := font.SizeUTF8(text[i : i+n])
+	if err != nil {
+		return
+	}
+	lx := x + int32(float32(pw)*scale)
+	fc := b.GetForeground()
+	renderer.SetDrawColor(fc.R, fc.G, fc.B, fc.A)
+	renderer.DrawLine(lx, y+h-1, lx+int32(float32(cw)*scale), y+h-1)
+}
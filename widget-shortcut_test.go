@@ -0,0 +1,158 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestParseKeyChord(t *testing.T) {
+	for _, c := range [][]string{
+		{"Ctrl+S", "Ctrl+S"},
+		{"ctrl+s", "Ctrl+S"},
+		{"F5", "F5"},
+		{"f12", "F12"},
+		{"Shift+Alt+N", "Alt+Shift+N"},
+		{"Control + Shift + z", "Ctrl+Shift+Z"},
+		{"Cmd+Enter", "Gui+Enter"},
+		{"Ctrl+Plus", "Ctrl+Plus"},
+		{"esc", "Esc"},
+		{"F", "F"},
+	} {
+		kc, err := ParseKeyChord(c[0])
+		if err != nil {
+			t.Fatalf("ParseKeyChord(%s) error %s", c[0], err.Error())
+		}
+		assertString(t, "ParseKeyChord "+c[0], kc.String(), c[1])
+	}
+	for _, c := range []string{"", "Ctrl+", "Hyper+S", "F13", "F5x", "Ctrl++"} {
+		_, err := ParseKeyChord(c)
+		assertBool(t, "ParseKeyChord error", c, err != nil, true)
+	}
+	kc, _ := ParseKeyChord("Ctrl+Shift+S")
+	assertBool(t, "NewKeyChord", "right ctrl and caps lock", NewKeyChord(sdl.K_s, sdl.KMOD_RCTRL|sdl.KMOD_LSHIFT|sdl.KMOD_CAPS) == kc, true)
+}
+
+func TestWidgetGroupShortcuts(t *testing.T) {
+	fired := ""
+	action := func(name string, used bool) func(SDL_KeyChord) bool {
+		return func(kc SDL_KeyChord) bool {
+			fired = fired + name + ":" + kc.String() + " "
+			return used
+		}
+	}
+	wg := NewWidgetGroup(nil)
+	sg1 := wg.NewWidgetSubGroup(0, 0, 400, 100, 1, WIDGET_STYLE_DRAW_NONE)
+	e := sg1.Add(NewSDLEntry(10, 10, 100, 24, 2, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Entry)
+	sg2 := wg.NewWidgetSubGroup(0, 100, 400, 100, 3, WIDGET_STYLE_DRAW_NONE)
+	k := sg2.Add(NewSDLKnob(10, 110, 50, 50, 4, 0, 100, 50, WIDGET_STYLE_DRAW_BORDER_AND_BG, nil))
+
+	_, err := wg.AddShortcut("Ctrl+S", nil, action("save", true))
+	assertBool(t, "AddShortcut", "Ctrl+S", err == nil, true)
+	_, err = wg.AddShortcut("ctrl+s", nil, action("save2", true))
+	assertBool(t, "AddShortcut", "duplicate global", err != nil, true)
+	assertString(t, "AddShortcut duplicate", err.Error(), "shortcut 'Ctrl+S' is already bound")
+	_, err = wg.AddShortcut("Ctrl+S", sg2, action("knobSave", true))
+	assertBool(t, "AddShortcut", "scoped over global", err == nil, true)
+	_, err = wg.AddShortcut("Ctrl+S", sg2, action("knobSave2", true))
+	assertString(t, "AddShortcut duplicate scoped", err.Error(), "shortcut 'Ctrl+S' is already bound in sub group 3")
+	hf5, _ := wg.AddShortcut("F5", nil, action("refresh", false))
+	_, err = wg.AddShortcut("Ctrl+Q", nil, nil)
+	assertBool(t, "AddShortcut", "nil action", err != nil, true)
+
+	// Global shortcut. The entry does not see the 's'
+	wg.moveFocus(e)
+	assertBool(t, "Ctrl+S", "used", wg.HandleEvent(keyEvent(sdl.K_s, sdl.KMOD_LCTRL, true)), true)
+	assertString(t, "Ctrl+S global", fired, "save:Ctrl+S ")
+	assertString(t, "Ctrl+S entry", e.GetText(), "")
+	// Key repeat and key up do not fire
+	ke := keyEvent(sdl.K_s, sdl.KMOD_LCTRL, true)
+	ke.Repeat = 1
+	wg.HandleEvent(ke)
+	wg.HandleEvent(keyEvent(sdl.K_s, sdl.KMOD_LCTRL, false))
+	assertString(t, "Ctrl+S repeat", fired, "save:Ctrl+S ")
+
+	// The scoped shortcut replaces the global one while the knob has the focus
+	fired = ""
+	wg.moveFocus(k)
+	wg.HandleEvent(keyEvent(sdl.K_s, sdl.KMOD_RCTRL, true))
+	assertString(t, "Ctrl+S scoped", fired, "knobSave:Ctrl+S ")
+	sg2.SetVisible(false)
+	fired = ""
+	wg.HandleEvent(keyEvent(sdl.K_s, sdl.KMOD_RCTRL, true))
+	assertString(t, "Ctrl+S scope hidden", fired, "save:Ctrl+S ")
+	sg2.SetVisible(true)
+
+	// An action that returns false passes the key on
+	fired = ""
+	assertBool(t, "F5", "not used", wg.HandleEvent(keyEvent(sdl.K_F5, 0, true)), false)
+	assertString(t, "F5", fired, "refresh:F5 ")
+	assertBool(t, "RemoveShortcut", "F5", wg.RemoveShortcut(hf5), true)
+	assertBool(t, "RemoveShortcut", "F5 again", wg.RemoveShortcut(hf5), false)
+	fired = ""
+	wg.HandleEvent(keyEvent(sdl.K_F5, 0, true))
+	assertString(t, "F5 removed", fired, "")
+
+	// A shortcut on a printable key does not also type it into the focused entry
+	wg.AddShortcut("Shift+G", nil, action("go", true))
+	wg.moveFocus(e)
+	fired = ""
+	wg.HandleEvent(keyEvent(sdl.K_g, sdl.KMOD_LSHIFT, true))
+	wg.HandleEvent(textEvent("G"))
+	wg.HandleEvent(keyEvent(sdl.K_g, sdl.KMOD_LSHIFT, false))
+	assertString(t, "Shift+G", fired, "go:Shift+G ")
+	assertString(t, "Shift+G entry", e.GetText(), "")
+	wg.HandleEvent(keyEvent(sdl.K_h, 0, true))
+	wg.HandleEvent(textEvent("h"))
+	assertString(t, "Typed after Shift+G", e.GetText(), "h")
+	// Ctrl+S has no text input. The next key is still typed
+	wg.HandleEvent(keyEvent(sdl.K_s, sdl.KMOD_LCTRL, true))
+	wg.HandleEvent(keyEvent(sdl.K_s, sdl.KMOD_LCTRL, false))
+	wg.HandleEvent(keyEvent(sdl.K_i, 0, true))
+	wg.HandleEvent(textEvent("i"))
+	assertString(t, "Typed after Ctrl+S", e.GetText(), "hi")
+}
+
+func TestWidgetGroupMnemonics(t *testing.T) {
+	clicks := ""
+	onClick := func(s string, id, x, y int32) bool {
+		clicks = clicks + fmt.Sprintf("%s:%d ", s, id)
+		return true
+	}
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 400, 100, 1, WIDGET_STYLE_DRAW_NONE)
+	save := sg.Add(NewSDLButton(10, 10, 50, 24, 2, "Save", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick))
+	sg.Add(NewSDLButton(70, 10, 50, 24, 3, "Exit", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, onClick)).SetMnemonic('X')
+	name := sg.Add(NewSDLLabel(130, 10, 50, 24, 4, "Name", ALIGN_LEFT, WIDGET_STYLE_DRAW_NONE))
+	save.SetMnemonic('s')
+	name.SetMnemonic('n')
+
+	assertInt(t, "mnemonicIndex", mnemonicIndex("Save As", 'a'), 1)
+	assertInt(t, "mnemonicIndex", mnemonicIndex("Save As", 'x'), -1)
+	assertInt(t, "mnemonicIndex", mnemonicIndex("Save", 0), -1)
+	assertInt(t, "Conflicts none", len(wg.ShortcutConflicts()), 0)
+
+	// Alt+letter clicks the button and focuses it. Case and Shift are ignored for the letter
+	assertBool(t, "Alt+X", "used", wg.HandleEvent(keyEvent(sdl.K_x, sdl.KMOD_LALT, true)), true)
+	assertString(t, "Alt+X", clicks, "Exit:3 ")
+	assertBool(t, "Alt+X", "focused", wg.GetFocusedWidget() != nil && wg.GetFocusedWidget().GetWidgetId() == 3, true)
+	clicks = ""
+	assertBool(t, "Ctrl+Alt+S", "not a mnemonic", wg.HandleEvent(keyEvent(sdl.K_s, sdl.KMOD_LALT|sdl.KMOD_LCTRL, true)), false)
+	assertString(t, "Ctrl+Alt+S", clicks, "")
+	// A label without a click handler that cannot be focused does nothing
+	assertBool(t, "Alt+N", "label", wg.HandleEvent(keyEvent(sdl.K_n, sdl.KMOD_RALT, true)), false)
+	// Disabled widgets are skipped
+	save.SetEnabled(false)
+	wg.HandleEvent(keyEvent(sdl.K_s, sdl.KMOD_LALT, true))
+	assertString(t, "Alt+S disabled", clicks, "")
+	save.SetEnabled(true)
+
+	// Conflicts
+	name.SetMnemonic('S')
+	wg.AddShortcut("Alt+X", nil, func(SDL_KeyChord) bool { return true })
+	assertString(t, "Conflicts", fmt.Sprint(wg.ShortcutConflicts()), "[mnemonic 'X' of widget 3 is hidden by shortcut 'Alt+X' mnemonic 'S' is used by widget 2 and widget 4]")
+	// The shortcut wins
+	wg.HandleEvent(keyEvent(sdl.K_x, sdl.KMOD_LALT, true))
+	assertString(t, "Alt+X shortcut", clicks, "")
+}
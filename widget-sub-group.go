@@ -17,6 +17,7 @@ type SDL_WidgetSubGroup struct {
 
 var _ SDL_Widget = (*SDL_WidgetSubGroup)(nil)    // Ensure SDL_Button 'is a' SDL_Widget
 var _ SDL_Container = (*SDL_WidgetSubGroup)(nil) // Ensure SDL_Button 'is a' SDL_Widget
+var _ SDL_TextInput = (*SDL_WidgetSubGroup)(nil) // Ensure SDL_WidgetSubGroup 'is a' SDL_TextInput
 
 func NewWidgetSubGroup(x, y, w, h, id int32, font *ttf.Font, style STATE_BITS) *SDL_WidgetSubGroup {
 	if font == nil {
@@ -72,7 +73,8 @@ func (wl *SDL_WidgetSubGroup) KeyPress(c int, ctrl, down bool) bool {
 	if wl.IsEnabled() {
 		w := wl.base
 		for w != nil {
-			if w.widget.CanFocus() && w.widget.IsFocused() {
+			_, isContainer := w.widget.(SDL_Container)
+			if isContainer || w.widget.CanFocus() && w.widget.IsFocused() {
 				if w.widget.KeyPress(c, ctrl, down) {
 					return true
 				}
@@ -83,6 +85,38 @@ func (wl *SDL_WidgetSubGroup) KeyPress(c int, ctrl, down bool) bool {
 	return false
 }
 
+func (wl *SDL_WidgetSubGroup) TextInput(text string) bool {
+	if wl.IsEnabled() {
+		w := wl.base
+		for w != nil {
+			ti, ok := w.widget.(SDL_TextInput)
+			if ok && w.widget.CanFocus() && w.widget.IsFocused() {
+				if ti.TextInput(text) {
+					return true
+				}
+			}
+			w = w.next
+		}
+	}
+	return false
+}
+
+func (wl *SDL_WidgetSubGroup) TextEditing(text string, start, length int32) bool {
+	if wl.IsEnabled() {
+		w := wl.base
+		for w != nil {
+			ti, ok := w.widget.(SDL_TextInput)
+			if ok && w.widget.CanFocus() && w.widget.IsFocused() {
+				if ti.TextEditing(text, start, length) {
+					return true
+				}
+			}
+			w = w.next
+		}
+	}
+	return false
+}
+
 func (wl *SDL_WidgetSubGroup) Scale(s float32) {
 	wl.SDL_WidgetBase.Scale(s)
 	w := wl.base
@@ -106,6 +140,9 @@ func (wl *SDL_WidgetSubGroup) Inside(x, y int32) (SDL_Widget, bool) {
 		linkedWidget := wl.base
 		for linkedWidget != nil {
 			ww := linkedWidget.widget
+			if wp, hasPopup := ww.(sdl_Popup); hasPopup && wp.insidePopup(x, y) {
+				return ww, true
+			}
 			wc, isContainer := ww.(SDL_Container)
 			if isContainer {
 				www, found := wc.Inside(x, y)
@@ -308,10 +345,17 @@ func (wl *SDL_WidgetSubGroup) NextFrame() {
 	}
 }
 
+/*
+The focused widget. Includes widgets in containers
+*/
 func (wl *SDL_WidgetSubGroup) GetFocusedWidget() SDL_Widget {
 	w := wl.base
 	for w != nil {
-		if w.widget.CanFocus() && w.widget.IsFocused() {
+		if wc, isContainer := w.widget.(SDL_Container); isContainer {
+			if f := wc.GetFocusedWidget(); f != nil {
+				return f
+			}
+		} else if w.widget.CanFocus() && w.widget.IsFocused() {
 			return w.widget
 		}
 		w = w.next
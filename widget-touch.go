@@ -0,0 +1,323 @@
+package go_sdl_widget
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type TOUCH_MODE int
+
+const (
+	TOUCH_MODE_NONE  TOUCH_MODE = iota // No finger down
+	TOUCH_MODE_PRESS                   // One finger down and not moved. Lifting it is a tap (click)
+	TOUCH_MODE_DRAG                    // The finger moved. Passed on as a mouse drag
+	TOUCH_MODE_SWIPE                   // The finger moved in a scrollable container. Scrolls it
+	TOUCH_MODE_PINCH                   // More than one finger. Zooms
+	TOUCH_MODE_DONE                    // Long press fired. Ignore the finger until it is lifted
+
+	touch_SLOP          int32   = 10   // Pixels a finger can move and still be a tap or long press
+	touch_LONG_PRESS_MS uint32  = 500  // A finger held this long without moving is a secondary (right) click
+	touch_DOUBLE_TAP_MS uint32  = 400  // A second tap this soon after the first is a double click
+	touch_SCROLL_STEP   float32 = 20   // Pixels of swipe for each scroll step
+	touch_FRICTION      float32 = 0.95 // Kinetic scroll speed kept each frame
+	touch_MIN_SPEED     float32 = 0.5  // Kinetic scrolling stops below this many pixels per frame
+	touch_FRAME_MS      float32 = 16   // Swipe speed is measured in pixels per frame at about 60 frames per second
+)
+
+type sdl_TouchPoint struct {
+	nx, ny float32 // Normalised 0..1
+}
+
+/****************************************************************************************
+* Touch state for an SDL_WidgetGroup.
+* The first finger down is the primary. It taps, long presses, drags and swipes.
+* A second finger turns it in to a pinch.
+**/
+type sdl_Touch struct {
+	fingers        map[sdl.FingerID]*sdl_TouchPoint
+	primary        sdl.FingerID
+	mode           TOUCH_MODE
+	seen           bool   // A finger event has been handled. Mouse events synthesised from touch are ignored
+	w, h           int32  // Window size. Normalised touch positions are multiplied by this
+	sizeSet        bool   // Set by SetTouchSize. Otherwise the renderer window size is used
+	downAt         uint32 // Timestamp of the primary finger down
+	startX, startY int32
+	lastX, lastY   int32
+	lastAt         uint32
+	lastTapAt      uint32
+	lastTapX       int32
+	lastTapY       int32
+	scroller       SDL_Scrollable // The container swiped or kinetic scrolling
+	accX, accY     float32        // Swipe pixels not yet scrolled
+	speedX, speedY float32        // Pixels per frame
+	kinetic        bool
+}
+
+func newTouch() *sdl_Touch {
+	return &sdl_Touch{fingers: make(map[sdl.FingerID]*sdl_TouchPoint)}
+}
+
+/*
+The window size used to convert normalised touch positions to pixels.
+If it is not set the size of the renderer's window, found when the group is drawn, is used.
+*/
+func (wg *SDL_WidgetGroup) SetTouchSize(w, h int32) {
+	wg.touch.w, wg.touch.h = w, h
+	wg.touch.sizeSet = w > 0 && h > 0
+}
+
+func (wg *SDL_WidgetGroup) GetTouchSize() (int32, int32) {
+	return wg.touch.w, wg.touch.h
+}
+
+func (wg *SDL_WidgetGroup) GetTouchMode() TOUCH_MODE {
+	return wg.touch.mode
+}
+
+func (t *sdl_Touch) toWindow(nx, ny float32) (int32, int32) {
+	return int32(nx * float32(t.w)), int32(ny * float32(t.h))
+}
+
+func (wg *SDL_WidgetGroup) finger(e *sdl.TouchFingerEvent) bool {
+	t := wg.touch
+	t.seen = true
+	x, y := t.toWindow(e.X, e.Y)
+	switch e.Type {
+	case sdl.FINGERDOWN:
+		t.kinetic = false
+		t.fingers[e.FingerID] = &sdl_TouchPoint{nx: e.X, ny: e.Y}
+		if len(t.fingers) > 1 {
+			if t.mode != TOUCH_MODE_PINCH {
+				wg.CancelDrag()
+				wg.cancelPress()
+				t.mode = TOUCH_MODE_PINCH
+			}
+			return true
+		}
+		return wg.fingerDown(e, x, y)
+	case sdl.FINGERMOTION:
+		p, ok := t.fingers[e.FingerID]
+		if !ok {
+			return false
+		}
+		p.nx, p.ny = e.X, e.Y
+		if e.FingerID != t.primary {
+			return true
+		}
+		return wg.fingerMotion(e, x, y)
+	case sdl.FINGERUP:
+		_, ok := t.fingers[e.FingerID]
+		if !ok {
+			return false
+		}
+		delete(t.fingers, e.FingerID)
+		used := true
+		if e.FingerID == t.primary {
+			used = wg.fingerUp(e, x, y)
+		}
+		if len(t.fingers) == 0 {
+			t.mode = TOUCH_MODE_NONE
+		}
+		return used
+	}
+	return false
+}
+
+/*
+Press the widget under the finger as the left mouse button would
+*/
+func (wg *SDL_WidgetGroup) fingerDown(e *sdl.TouchFingerEvent, x, y int32) bool {
+	t := wg.touch
+	t.primary = e.FingerID
+	t.mode = TOUCH_MODE_PRESS
+	t.downAt, t.lastAt = e.Timestamp, e.Timestamp
+	t.startX, t.startY, t.lastX, t.lastY = x, y, x, y
+	t.accX, t.accY, t.speedX, t.speedY = 0, 0, 0, 0
+	t.scroller = wg.scrollContainerAt(x, y)
+	var clicks uint8 = 1
+	if t.lastTapAt > 0 && e.Timestamp-t.lastTapAt < touch_DOUBLE_TAP_MS && abs32(x-t.lastTapX) < touch_SLOP*2 && abs32(y-t.lastTapY) < touch_SLOP*2 {
+		clicks = 2
+		t.lastTapAt = 0
+	}
+	wg.mouseX, wg.mouseY = x, y
+	return wg.mouseButtonDown(&sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONDOWN, Button: sdl.BUTTON_LEFT, State: sdl.PRESSED, Clicks: clicks, X: x, Y: y})
+}
+
+/*
+A finger that moves beyond the slop swipes the scrollable container it is in, if there is one.
+Otherwise it drags the widget it went down on. A finger held still becomes a long press.
+*/
+func (wg *SDL_WidgetGroup) fingerMotion(e *sdl.TouchFingerEvent, x, y int32) bool {
+	t := wg.touch
+	dx, dy := x-t.lastX, y-t.lastY
+	dt := e.Timestamp - t.lastAt
+	t.lastX, t.lastY, t.lastAt = x, y, e.Timestamp
+	switch t.mode {
+	case TOUCH_MODE_PRESS:
+		if abs32(x-t.startX) < touch_SLOP && abs32(y-t.startY) < touch_SLOP {
+			if e.Timestamp-t.downAt >= touch_LONG_PRESS_MS {
+				wg.longPress(t.startX, t.startY)
+			}
+			return true
+		}
+		if t.scroller != nil {
+			wg.cancelPress()
+			t.mode = TOUCH_MODE_SWIPE
+			// Scroll from where the finger went down
+			dx, dy = x-t.startX, y-t.startY
+		} else {
+			t.mode = TOUCH_MODE_DRAG
+		}
+	case TOUCH_MODE_DONE, TOUCH_MODE_PINCH:
+		return true
+	}
+	if t.mode == TOUCH_MODE_SWIPE {
+		sx, sy := float32(dx), float32(dy)
+		if dt > 0 {
+			sx, sy = sx*touch_FRAME_MS/float32(dt), sy*touch_FRAME_MS/float32(dt)
+		}
+		// Smooth the speed so one jittery event does not decide the fling
+		t.speedX, t.speedY = (t.speedX+sx*3)/4, (t.speedY+sy*3)/4
+		t.swipe(float32(dx), float32(dy))
+		return true
+	}
+	wg.mouseX, wg.mouseY = x, y
+	return wg.mouseMotion(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: x, Y: y, State: sdl.BUTTON_LEFT})
+}
+
+/*
+Lifting the finger is a click (tap), a long press if it was held long enough,
+the end of a drag, or the start of kinetic scrolling after a swipe.
+*/
+func (wg *SDL_WidgetGroup) fingerUp(e *sdl.TouchFingerEvent, x, y int32) bool {
+	t := wg.touch
+	up := &sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONUP, Button: sdl.BUTTON_LEFT, State: sdl.RELEASED, Clicks: 1, X: x, Y: y}
+	switch t.mode {
+	case TOUCH_MODE_PRESS:
+		if e.Timestamp-t.downAt >= touch_LONG_PRESS_MS {
+			return wg.longPress(t.startX, t.startY)
+		}
+		if wg.mouseData.GetClickCount() < 2 {
+			t.lastTapAt, t.lastTapX, t.lastTapY = e.Timestamp, x, y
+		}
+		return wg.mouseButtonUp(up)
+	case TOUCH_MODE_DRAG:
+		return wg.mouseButtonUp(up)
+	case TOUCH_MODE_SWIPE:
+		t.kinetic = abs32f(t.speedX) >= touch_MIN_SPEED || abs32f(t.speedY) >= touch_MIN_SPEED
+		return true
+	}
+	return true
+}
+
+/*
+Cancel the press and right click the widget under the finger
+*/
+func (wg *SDL_WidgetGroup) longPress(x, y int32) bool {
+	wg.cancelPress()
+	wg.touch.mode = TOUCH_MODE_DONE
+	w := wg.InsideWidget(x, y)
+	if w == nil {
+		return false
+	}
+	return w.Click(&SDL_MouseData{x: x, y: y, button: sdl.BUTTON_RIGHT, clickCount: 1, modifiers: wg.modifiers})
+}
+
+/*
+Scroll whole steps and keep the rest for the next move
+*/
+func (t *sdl_Touch) swipe(dx, dy float32) bool {
+	if t.scroller == nil {
+		return false
+	}
+	t.accX, t.accY = t.accX+dx, t.accY+dy
+	sx, sy := int32(t.accX/touch_SCROLL_STEP), int32(t.accY/touch_SCROLL_STEP)
+	if sx == 0 && sy == 0 {
+		return true
+	}
+	t.accX, t.accY = t.accX-float32(sx)*touch_SCROLL_STEP, t.accY-float32(sy)*touch_SCROLL_STEP
+	return t.scroller.Scroll(sx, sy, float32(sy))
+}
+
+/*
+Called each frame from NextFrame. Kinetic scrolling slows down and stops at the end of the container.
+Also fires a long press for a finger held still, without waiting for it to move or lift.
+*/
+func (wg *SDL_WidgetGroup) touchFrame() {
+	t := wg.touch
+	if t.kinetic {
+		if !t.swipe(t.speedX, t.speedY) {
+			t.kinetic = false
+		}
+		t.speedX, t.speedY = t.speedX*touch_FRICTION, t.speedY*touch_FRICTION
+		if abs32f(t.speedX) < touch_MIN_SPEED && abs32f(t.speedY) < touch_MIN_SPEED {
+			t.kinetic = false
+		}
+	}
+	if t.mode == TOUCH_MODE_PRESS && sdl.GetTicks64() >= uint64(t.downAt)+uint64(touch_LONG_PRESS_MS) {
+		wg.longPress(t.startX, t.startY)
+	}
+}
+
+/*
+Zoom the SDL_Zoomable at the centre of a two finger pinch. If the widget there does not zoom
+it is passed to the containers it is in. The factor is the change in distance between the fingers.
+*/
+func (wg *SDL_WidgetGroup) gesture(e *sdl.MultiGestureEvent) bool {
+	t := wg.touch
+	t.seen = true
+	if e.NumFingers < 2 || e.DDist == 0 {
+		return false
+	}
+	var p1, p2 *sdl_TouchPoint
+	for _, p := range t.fingers {
+		if p1 == nil {
+			p1 = p
+		} else if p2 == nil {
+			p2 = p
+		}
+	}
+	if p2 == nil {
+		return false
+	}
+	d := float32(math.Hypot(float64(p1.nx-p2.nx), float64(p1.ny-p2.ny)))
+	if d <= 0 || d-e.DDist <= 0 {
+		return false
+	}
+	x, y := t.toWindow(e.X, e.Y)
+	return wg.Zoom(x, y, d/(d-e.DDist))
+}
+
+/*
+Zoom the widget at x,y. If it is not SDL_Zoomable, or does not use the zoom, it is passed to the
+container it is in and so on up to the sub group. Returns true if a widget used it.
+*/
+func (wg *SDL_WidgetGroup) Zoom(x, y int32, factor float32) bool {
+	return wg.bubbleAt(x, y, func(w SDL_Widget) bool {
+		z, ok := w.(SDL_Zoomable)
+		return ok && w.IsEnabled() && z.Zoom(factor, x, y)
+	}) != nil
+}
+
+/*
+The nearest container at x,y that can be scrolled. A swipe in it scrolls it rather than dragging the widget.
+*/
+func (wg *SDL_WidgetGroup) scrollContainerAt(x, y int32) SDL_Scrollable {
+	w := wg.bubbleAt(x, y, func(w SDL_Widget) bool {
+		_, isContainer := w.(SDL_Container)
+		_, ok := w.(SDL_Scrollable)
+		return isContainer && ok && w.IsEnabled()
+	})
+	if w == nil {
+		return nil
+	}
+	return w.(SDL_Scrollable)
+}
+
+func abs32f(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,203 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Touch positions in the tests are pixels in a 1000 x 1000 window
+*/
+func fingerEvent(typ uint32, id sdl.FingerID, x, y int32, ts uint32) *sdl.TouchFingerEvent {
+	return &sdl.TouchFingerEvent{Type: typ, FingerID: id, X: float32(x) / 1000, Y: float32(y) / 1000, Timestamp: ts}
+}
+
+func tap(wg *SDL_WidgetGroup, x, y int32, ts uint32) {
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 1, x, y, ts))
+	wg.HandleEvent(fingerEvent(sdl.FINGERUP, 1, x, y, ts+50))
+}
+
+func TestTouchTapAndLongPress(t *testing.T) {
+	clicks := ""
+	wg := NewWidgetGroup(nil)
+	wg.SetTouchSize(1000, 1000)
+	sg := wg.NewWidgetSubGroup(0, 0, 1000, 1000, 1, WIDGET_STYLE_DRAW_NONE)
+	b := sg.Add(NewSDLButton(100, 100, 50, 24, 2, "B", WIDGET_STYLE_DRAW_BORDER_AND_BG, 0, func(s string, id, x, y int32) bool {
+		clicks = clicks + fmt.Sprintf("left:%d,%d ", x, y)
+		return true
+	})).(*SDL_Button)
+	b.AddRightClickListener(func(ce *SDL_ClickEvent) bool {
+		clicks = clicks + fmt.Sprintf("right:%d,%d ", ce.GetX(), ce.GetY())
+		return true
+	})
+
+	tap(wg, 110, 110, 1000)
+	assertString(t, "Tap", clicks, "left:110,110 ")
+	// SDL also sends mouse events made from the touch. They are ignored
+	synth := mouseDown(110, 110)
+	synth.Which = sdl.TOUCH_MOUSEID
+	assertBool(t, "Synthetic mouse", "used", wg.HandleEvent(synth), false)
+	synth = mouseUp(110, 110)
+	synth.Which = sdl.TOUCH_MOUSEID
+	wg.HandleEvent(synth)
+	assertString(t, "Synthetic mouse", clicks, "left:110,110 ")
+	// A real mouse still works
+	wg.HandleEvent(mouseDown(120, 110))
+	wg.HandleEvent(mouseUp(120, 110))
+	assertString(t, "Mouse", clicks, "left:110,110 left:120,110 ")
+
+	// Held without moving. Fires on the finger up
+	clicks = ""
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 1, 110, 110, 2000))
+	wg.HandleEvent(fingerEvent(sdl.FINGERUP, 1, 110, 110, 2600))
+	assertString(t, "Long press on up", clicks, "right:110,110 ")
+	assertBool(t, "Long press", "clicked", b.IsClicked(), false)
+
+	// Held with a small move. Fires on the move and the up does nothing
+	clicks = ""
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 1, 110, 110, 3000))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 113, 112, 3200))
+	assertString(t, "Small move", clicks, "")
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 112, 112, 3600))
+	assertInt(t, "Long press", int(wg.GetTouchMode()), int(TOUCH_MODE_DONE))
+	wg.HandleEvent(fingerEvent(sdl.FINGERUP, 1, 112, 112, 3700))
+	assertString(t, "Long press on move", clicks, "right:110,110 ")
+	assertInt(t, "Lifted", int(wg.GetTouchMode()), int(TOUCH_MODE_NONE))
+
+	// Moved off the button. It is a drag, not a tap
+	clicks = ""
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 1, 110, 110, 4000))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 300, 300, 4100))
+	assertInt(t, "Drag", int(wg.GetTouchMode()), int(TOUCH_MODE_DRAG))
+	wg.HandleEvent(fingerEvent(sdl.FINGERUP, 1, 300, 300, 4200))
+	assertString(t, "Drag off", clicks, "")
+}
+
+func TestTouchEntry(t *testing.T) {
+	wg := NewWidgetGroup(nil)
+	wg.SetTouchSize(1000, 1000)
+	sg := wg.NewWidgetSubGroup(0, 0, 1000, 1000, 1, WIDGET_STYLE_DRAW_NONE)
+	e := sg.Add(NewSDLEntry(10, 200, 300, 24, 2, "hello world", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)).(*SDL_Entry)
+	layoutEntry(e)
+
+	// Double tap is a double click. It selects as a mouse double click does
+	tap(wg, 30, 210, 1000)
+	assertBool(t, "Tap", "focused", e.IsFocused(), true)
+	assertString(t, "Tap", e.GetSelectedText(), "")
+	tap(wg, 32, 211, 1200)
+	assertBool(t, "Double tap", "selected", e.GetSelectedText() != "", true)
+	// Too slow for a double tap
+	tap(wg, 100, 210, 2000)
+	tap(wg, 100, 210, 2500)
+	assertString(t, "Slow taps", e.GetSelectedText(), "")
+
+	// Drag selects
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 1, 12, 210, 3000))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 60, 210, 3050))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 300, 210, 3100))
+	wg.HandleEvent(fingerEvent(sdl.FINGERUP, 1, 300, 210, 3150))
+	assertString(t, "Drag select", e.GetSelectedText(), "hello world")
+}
+
+func TestTouchSwipeFileList(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 30; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%02d.txt", i)), []byte("x"), 0644)
+	}
+	selected := ""
+	fl, err := NewFileList(0, 0, 20, 10, dir, nil, WIDGET_STYLE_DRAW_BG, func(s string, rc FILE_LIST_RESPONSE_CODE, id int32) bool {
+		selected = s
+		return false
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fl.Reload(dir)
+	wg := NewWidgetGroup(nil)
+	wg.SetTouchSize(1000, 1000)
+	wg.NewWidgetSubGroup(0, 0, 1000, 1000, 1, WIDGET_STYLE_DRAW_NONE).Add(fl)
+
+	// 100 pixels up is 5 rows. Scrolled from where the finger went down
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 1, 10, 300, 1000))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 10, 285, 1016))
+	assertInt(t, "Swipe", int(wg.GetTouchMode()), int(TOUCH_MODE_SWIPE))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 10, 240, 1032))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 10, 200, 1048))
+	assertInt(t, "Swipe", fl.GetScrollRows(), 5)
+	wg.HandleEvent(fingerEvent(sdl.FINGERUP, 1, 10, 200, 1064))
+	assertString(t, "Row not clicked", selected, "")
+
+	// Fling. Keeps scrolling and slows down
+	wg.NextFrame()
+	wg.NextFrame()
+	after2 := fl.GetScrollRows()
+	assertBool(t, "Kinetic", "scrolled", after2 > 5, true)
+	for i := 0; i < 200; i++ {
+		wg.NextFrame()
+	}
+	assertInt(t, "Kinetic stops at the end", fl.GetScrollRows(), 30)
+	assertBool(t, "Kinetic", "stopped", wg.touch.kinetic, false)
+
+	// Swipe down (from the one row left) scrolls back. A finger down stops kinetic scrolling
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 1, 10, 30, 2000))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 10, 130, 2100))
+	wg.HandleEvent(fingerEvent(sdl.FINGERUP, 1, 10, 130, 2200))
+	assertInt(t, "Swipe down", fl.GetScrollRows(), 25)
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 1, 10, 30, 3000))
+	wg.NextFrame()
+	assertBool(t, "Finger down", "kinetic", wg.touch.kinetic, false)
+}
+
+func TestTouchPinch(t *testing.T) {
+	wg := NewWidgetGroup(nil)
+	wg.SetTouchSize(1000, 1000)
+	sg := wg.NewWidgetSubGroup(0, 0, 1000, 1000, 1, WIDGET_STYLE_DRAW_NONE)
+	c := sg.Add(NewSDLCanvas(100, 100, 800, 800, 2, WIDGET_STYLE_DRAW_NONE, nil)).(*SDL_Canvas)
+	r := c.AddRect(3, 410, 410, 20, 20, nil, true)
+
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 1, 400, 500, 1000))
+	wg.HandleEvent(fingerEvent(sdl.FINGERDOWN, 2, 600, 500, 1010))
+	assertInt(t, "Two fingers", int(wg.GetTouchMode()), int(TOUCH_MODE_PINCH))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 300, 500, 1020))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 2, 700, 500, 1020))
+	// Distance 0.2 to 0.4. Zoom x2 about the centre (500,500). Local 400,400
+	assertBool(t, "Pinch", "used", wg.HandleEvent(&sdl.MultiGestureEvent{Type: sdl.MULTIGESTURE, DDist: 0.2, X: 0.5, Y: 0.5, NumFingers: 2}), true)
+	assertString(t, "Pinch", fmt.Sprint(*r.GetRect()), "{420 420 40 40}")
+
+	var factor float32
+	c.SetOnZoom(func(f float32, x, y int32) bool {
+		factor = f
+		return true
+	})
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 1, 400, 500, 1030))
+	wg.HandleEvent(fingerEvent(sdl.FINGERMOTION, 2, 600, 500, 1030))
+	wg.HandleEvent(&sdl.MultiGestureEvent{Type: sdl.MULTIGESTURE, DDist: -0.2, X: 0.5, Y: 0.5, NumFingers: 2})
+	assertFloat(t, "Pinch in", float64(factor), 0.5)
+
+	wg.HandleEvent(fingerEvent(sdl.FINGERUP, 1, 400, 500, 1040))
+	assertInt(t, "One finger left", int(wg.GetTouchMode()), int(TOUCH_MODE_PINCH))
+	wg.HandleEvent(fingerEvent(sdl.FINGERUP, 2, 600, 500, 1040))
+	assertInt(t, "Lifted", int(wg.GetTouchMode()), int(TOUCH_MODE_NONE))
+}
+
+func TestTouchSize(t *testing.T) {
+	wg := NewWidgetGroup(nil)
+	w, h := wg.GetTouchSize()
+	assertString(t, "Not set", fmt.Sprintf("%dx%d", w, h), "0x0")
+	wg.SetTouchSize(1024, 600)
+	w, h = wg.GetTouchSize()
+	assertString(t, "Set size", fmt.Sprintf("%dx%d", w, h), "1024x600")
+	assertBool(t, "Set size", "sizeSet", wg.touch.sizeSet, true)
+	// Zero goes back to using the window size when drawn
+	wg.SetTouchSize(0, 0)
+	assertBool(t, "Zero size", "sizeSet", wg.touch.sizeSet, false)
+
+	// Normalised positions are multiplied by the size
+	wg.SetTouchSize(1000, 500)
+	x, y := wg.touch.toWindow(0.25, 0.5)
+	assertString(t, "toWindow", fmt.Sprintf("%d,%d", x, y), "250,250")
+}
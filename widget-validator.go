@@ -0,0 +1,99 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type ENTRY_VALIDATE_ON int
+
+const (
+	ENTRY_VALIDATE_ON_CHANGE ENTRY_VALIDATE_ON = iota // Validate after every change and on finish
+	ENTRY_VALIDATE_ON_FINISH                          // Validate only on ENTRY_EVENT_FINISH (Return) and ENTRY_EVENT_UN_FOCUS
+)
+
+/****************************************************************************************
+* SDL_Validator code
+* A validator returns an error with a message for the user if the text is not valid.
+* Any func(string) error can be used as a custom validator.
+* If msg is "" a default message is used.
+**/
+type SDL_Validator func(string) error
+
+func validatorError(msg, def string, args ...interface{}) error {
+	if msg == "" {
+		return fmt.Errorf(def, args...)
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+func ValidateRequired(msg string) SDL_Validator {
+	return func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return validatorError(msg, "a value is required")
+		}
+		return nil
+	}
+}
+
+/*
+Text must be at least min chars (runes) long. Empty text is valid. Use ValidateRequired as well if it is not.
+*/
+func ValidateMinLength(min int, msg string) SDL_Validator {
+	return func(text string) error {
+		l := utf8.RuneCountInString(text)
+		if l > 0 && l < min {
+			return validatorError(msg, "must be at least %d characters", min)
+		}
+		return nil
+	}
+}
+
+func ValidateMaxLength(max int, msg string) SDL_Validator {
+	return func(text string) error {
+		if utf8.RuneCountInString(text) > max {
+			return validatorError(msg, "must be at most %d characters", max)
+		}
+		return nil
+	}
+}
+
+/*
+Text must match the regular expression. Empty text is valid.
+Returns an error if the expression does not compile.
+*/
+func ValidateRegex(expr string, msg string) (SDL_Validator, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(text string) error {
+		if text != "" && !re.MatchString(text) {
+			return validatorError(msg, "invalid format")
+		}
+		return nil
+	}, nil
+}
+
+/*
+Text must be a number between min and max inclusive. Empty text is valid.
+*/
+func ValidateRange(min, max float64, msg string) SDL_Validator {
+	return func(text string) error {
+		s := strings.TrimSpace(text)
+		if s == "" {
+			return nil
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return validatorError(msg, "must be a number")
+		}
+		if v < min || v > max {
+			return validatorError(msg, "must be between %s and %s", strconv.FormatFloat(min, 'f', -1, 64), strconv.FormatFloat(max, 'f', -1, 64))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+package go_sdl_widget
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func assertValid(t *testing.T, message1 string, v SDL_Validator, text string, expected string) {
+	err := v(text)
+	msg := ""
+	if err != nil {
+		msg = err.Error()
+	}
+	if msg != expected {
+		t.Errorf("%s '%s': Actual '%s' Expected '%s'", message1, text, msg, expected)
+	}
+}
+
+func TestValidators(t *testing.T) {
+	assertValid(t, "Required", ValidateRequired(""), "", "a value is required")
+	assertValid(t, "Required", ValidateRequired("Name needed"), "  ", "Name needed")
+	assertValid(t, "Required", ValidateRequired(""), "x", "")
+	assertValid(t, "Min", ValidateMinLength(3, ""), "日本", "must be at least 3 characters")
+	assertValid(t, "Min", ValidateMinLength(3, ""), "日本語", "")
+	assertValid(t, "Min", ValidateMinLength(3, ""), "", "")
+	assertValid(t, "Max", ValidateMaxLength(2, ""), "abc", "must be at most 2 characters")
+	assertValid(t, "Max", ValidateMaxLength(2, ""), "日本", "")
+	assertValid(t, "Range", ValidateRange(0, 10.5, ""), "11", "must be between 0 and 10.5")
+	assertValid(t, "Range", ValidateRange(0, 10.5, ""), "x", "must be a number")
+	assertValid(t, "Range", ValidateRange(0, 10.5, ""), " 10.5 ", "")
+
+	re, err := ValidateRegex("^[0-9]{4}$", "Four digits")
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+	assertValid(t, "Regex", re, "123", "Four digits")
+	assertValid(t, "Regex", re, "1234", "")
+	_, err = ValidateRegex("[", "")
+	if err == nil {
+		t.Errorf("Invalid regex should return an error")
+	}
+}
+
+func TestEntryValidators(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	e.AddValidator(ValidateRequired("Required"), ValidateMaxLength(3, ""), func(s string) error {
+		if strings.Contains(s, "x") {
+			return fmt.Errorf("no x")
+		}
+		return nil
+	})
+	e.SetFocused(true)
+	typeString(e, "ab")
+	assertBool(t, "Valid", "IsError", e.IsError(), false)
+	typeString(e, "x")
+	assertBool(t, "Custom", "IsError", e.IsError(), true)
+	assertString(t, "Custom message", e.GetErrorMessage(), "no x")
+	e.KeyPress(sdl.K_BACKSPACE, true, true)
+	assertString(t, "Cleared message", e.GetErrorMessage(), "")
+	typeString(e, "cd")
+	assertString(t, "Max message", e.GetErrorMessage(), "must be at most 3 characters")
+
+	e.SetValidateOn(ENTRY_VALIDATE_ON_FINISH)
+	e.SetText("")
+	e.KeyPress(sdl.K_RETURN, true, true)
+	assertString(t, "Finish", e.GetErrorMessage(), "Required")
+	typeString(e, "xx")
+	assertString(t, "Not on change", e.GetErrorMessage(), "Required")
+	e.SetFocused(false)
+	assertString(t, "Un focus", e.GetErrorMessage(), "no x")
+	assertBool(t, "Un focus", "IsError", e.IsError(), true)
+
+	e.ClearValidators()
+	assertBool(t, "Cleared", "IsError", e.IsError(), false)
+}
+
+func TestEntryValidateOnFocus(t *testing.T) {
+	wg := NewWidgetGroup(nil)
+	sg := wg.NewWidgetSubGroup(0, 0, 400, 100, 1, WIDGET_STYLE_DRAW_NONE)
+	onChange := func(old, new string, ev ENTRY_EVENT_TYPE) (string, error) {
+		return new, nil
+	}
+	e1 := sg.Add(NewSDLEntry(10, 10, 100, 20, 2, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, onChange)).(*SDL_Entry)
+	e2 := sg.Add(NewSDLEntry(10, 40, 100, 20, 3, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, onChange)).(*SDL_Entry)
+	e1.AddValidator(ValidateRequired("Required 1"))
+	e2.AddValidator(ValidateRequired("Required 2"))
+	e1.SetValidateOn(ENTRY_VALIDATE_ON_FINISH)
+
+	// Focusing e1 un focuses e2 which never had the focus. It is not validated
+	wg.SetFocusedId(2)
+	assertBool(t, "Never focused", "IsError", e2.IsError(), false)
+	wg.SetFocusedId(3)
+	assertString(t, "Focus lost", e1.GetErrorMessage(), "Required 1")
+	assertBool(t, "Focused", "IsError", e2.IsError(), false)
+	wg.ClearFocus()
+	assertString(t, "Focus lost", e2.GetErrorMessage(), "Required 2")
+
+	// The finish error stays while typing, even though onChange accepts the change
+	wg.SetFocusedId(2)
+	typeString(e1, "a")
+	assertString(t, "Typed", e1.GetErrorMessage(), "Required 1")
+	e1.KeyPress(sdl.K_RETURN, true, true)
+	assertString(t, "Finish", e1.GetErrorMessage(), "")
+}
+
+func TestEntryOnChangeErrorMessage(t *testing.T) {
+	e := NewSDLEntry(0, 0, 200, 20, 1, "", WIDGET_STYLE_DRAW_BORDER_AND_BG, func(old, new string, ev ENTRY_EVENT_TYPE) (string, error) {
+		if new == "a" {
+			return new, fmt.Errorf("not a")
+		}
+		return new, nil
+	})
+	e.SetFocused(true)
+	typeString(e, "a")
+	assertString(t, "onChange error", e.GetErrorMessage(), "not a")
+	typeString(e, "b")
+	assertString(t, "onChange no error", e.GetErrorMessage(), "")
+}
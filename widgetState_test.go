@@ -44,6 +44,26 @@ func TestWidgetStateColourIndex(t *testing.T) {
 	w.SetFocused(true)
 	assertStateColour(t, "Error", w.getResourceColourStateIndex(), WIDGET_COLOUR_INDEX_ERROR)
 
+	w = NewSDLEntry(0, 0, 0, 0, 99, "HI", WIDGET_STYLE_DRAW_BORDER_AND_BG, nil)
+	w.SetHover(true)
+	assertStateColour(t, "Hover", w.getResourceColourStateIndex(), WIDGET_COLOUR_INDEX_HOVER)
+	w.SetFocused(true)
+	assertStateColour(t, "Hover + Focused", w.getResourceColourStateIndex(), WIDGET_COLOUR_INDEX_FOCUS)
+	w.SetFocused(false)
+	w.SetError(true)
+	assertStateColour(t, "Hover + Error", w.getResourceColourStateIndex(), WIDGET_COLOUR_INDEX_ERROR)
+	w.SetError(false)
+	w.SetEnabled(false)
+	assertStateColour(t, "Hover + Disabled", w.getResourceColourStateIndex(), WIDGET_COLOUR_INDEX_DISABLE)
+	w.SetEnabled(true)
+	w.SetHover(false)
+	assertStateColour(t, "Not Hover", w.getResourceColourStateIndex(), WIDGET_COLOUR_INDEX_ENABLED)
+
+	l := NewSDLLabel(0, 0, 0, 0, 99, "HI", ALIGN_LEFT, WIDGET_STYLE_DRAW_BORDER_AND_BG)
+	l.SetHover(true)
+	assertStateColour(t, "Hover label", l.getResourceColourStateIndex(), WIDGET_COLOUR_INDEX_ENABLED)
+	l.SetOnClick(func(s string, i1, i2, i3 int32) bool { return true })
+	assertStateColour(t, "Hover label with onClick", l.getResourceColourStateIndex(), WIDGET_COLOUR_INDEX_HOVER)
 }
 
 func TestWidgetBaseState(t *testing.T) {
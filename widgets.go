@@ -98,10 +98,9 @@ func (s *SDL_Shape) Rotate(angle int) {
 	sinA := math.Sin(rad)
 	cosA := math.Cos(rad)
 	for i := 0; i < len(s.vxIn); i++ {
-		px = float64(s.vxIn[i])
-		py = float64(s.vyIn[i])
-		s.vxIn[i] = int16(cosA*px - sinA*py)
-		s.vyIn[i] = int16(sinA*px + cosA*py)
+		px, py = rotatePoint(float64(s.vxIn[i]), float64(s.vyIn[i]), sinA, cosA)
+		s.vxIn[i] = int16(px)
+		s.vyIn[i] = int16(py)
 	}
 	s.validRect = nil
 }
@@ -213,6 +212,9 @@ func (b *SDL_Label) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
 			cachedTexture.texture,
 			&sdl.Rect{X: 0, Y: 0, W: rw, H: cachedTexture.h},
 			&sdl.Rect{X: b.x + tx, Y: b.y + tm, W: sw, H: sh})
+		if rw > 0 {
+			b.drawMnemonic(renderer, font, b.text, b.x+tx, b.y+tm, sh, float32(sw)/float32(rw))
+		}
 		if b.ShouldDrawBorder() {
 			bc := b.GetBorderColour()
 			renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
@@ -239,7 +241,7 @@ var _ SDL_Widget = (*SDL_Button)(nil)     // Ensure SDL_Button 'is a' SDL_Widget
 
 func NewSDLButton(x, y, w, h, id int32, text string, style STATE_BITS, deBounce int, onClick func(string, int32, int32, int32) bool) *SDL_Button {
 	but := &SDL_Button{text: text, backgroundImage: ""}
-	but.SDL_WidgetBase = initBase(x, y, w, h, id, but, deBounce, false, style, onClick)
+	but.SDL_WidgetBase = initBase(x, y, w, h, id, but, deBounce, true, style, onClick)
 	return but
 }
 
@@ -261,6 +263,7 @@ func (b *SDL_Button) GetBackgroundImage() string {
 
 func (b *SDL_Button) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
 	if b.IsVisible() {
+		b.releaseClicked()
 		var image *sdl.Texture
 		var imagew, imageh int32
 		var err error
@@ -312,13 +315,20 @@ func (b *SDL_Button) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
 		tw := int32(float32(ctwe.w) * (bh / float32(ctwe.h)))
 		tx := (b.w - tw) / 2
 		ty := (b.h - th) / 2
+		if b.IsClicked() || b.IsPressed() {
+			// Pressed. Move the text down and right a little
+			tx++
+			ty++
+		}
 		renderer.Copy(ctwe.texture, nil, &sdl.Rect{X: b.x + tx, Y: b.y + ty, W: tw, H: th})
+		b.drawMnemonic(renderer, font, b.text, b.x+tx, b.y+ty, th, float32(tw)/float32(ctwe.w))
 		if b.ShouldDrawBorder() && b.backgroundImage == "" {
 			bc := b.GetBorderColour()
 			renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
 			renderer.DrawRect(&sdl.Rect{X: b.x + 1, Y: b.y + 1, W: b.w - 2, H: b.h - 2})
 			renderer.DrawRect(&sdl.Rect{X: b.x + 2, Y: b.y + 2, W: b.w - 4, H: b.h - 4})
 		}
+		b.drawFocusRing(renderer)
 	}
 	return nil
 }
@@ -340,7 +350,7 @@ var _ SDL_Widget = (*SDL_Image)(nil)      // Ensure SDL_Image 'is a' SDL_Widget
 
 func NewSDLImage(x, y, w, h, id int32, textureName string, frame, frameCount int32, style STATE_BITS, deBounce int, onClick func(string, int32, int32, int32) bool) *SDL_Image {
 	but := &SDL_Image{textureName: textureName, frame: frame, frameCount: frameCount}
-	but.SDL_WidgetBase = initBase(x, y, w, h, id, but, deBounce, false, style, onClick)
+	but.SDL_WidgetBase = initBase(x, y, w, h, id, but, deBounce, true, style, onClick)
 	return but
 }
 
@@ -373,6 +383,7 @@ func (im *SDL_Image) GetFrameCount() int32 {
 
 func (im *SDL_Image) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
 	if im.IsVisible() {
+		im.releaseClicked()
 		tn := im.textureName
 		if !im.IsEnabled() {
 			tn = tn + ".dis"
@@ -397,6 +408,11 @@ func (im *SDL_Image) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
 		if im.ShouldDrawBorder() {
 			outRect = widgetShrinkRect(outRect, 4)
 		}
+		if im.IsClicked() || im.IsPressed() {
+			// Pressed. Move the image down and right a little
+			outRect.X++
+			outRect.Y++
+		}
 		if im.ShouldDrawBackground() {
 			bc := im.GetBackground()
 			renderer.SetDrawColor(bc.R, bc.G, bc.B, bc.A)
@@ -417,6 +433,7 @@ func (im *SDL_Image) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
 			renderer.DrawRect(&sdl.Rect{X: im.x + 1, Y: im.y + 1, W: im.w - 2, H: im.h - 2})
 			renderer.DrawRect(&sdl.Rect{X: im.x + 2, Y: im.y + 2, W: im.w - 4, H: im.h - 4})
 		}
+		im.drawFocusRing(renderer)
 	}
 	return nil
 }
@@ -455,6 +472,14 @@ func (sep *SDL_Separator) Draw(renderer *sdl.Renderer, font *ttf.Font) error {
 	return nil
 }
 
+/*
+Rotate a point around 0,0. sinA and cosA are the Sin and Cos of the angle in radians.
+Screen y is down so a positive angle rotates clockwise.
+*/
+func rotatePoint(px, py, sinA, cosA float64) (float64, float64) {
+	return cosA*px - sinA*py, sinA*px + cosA*py
+}
+
 func ifZeroUseN(i, n int32) int32 {
 	if i == 0 {
 		return n
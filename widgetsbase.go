@@ -3,7 +3,8 @@ package go_sdl_widget
 import (
 	"fmt"
 	"math"
-	"time"
+	"sync"
+	"sync/atomic"
 
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/veandco/go-sdl2/ttf"
@@ -15,6 +16,8 @@ type KBD_KEY_MODE int
 type ENTRY_EVENT_TYPE int
 type STATE_BITS uint16
 type LOG_LEVEL int
+type CLICK_KIND int
+type SDL_ListenerHandle uint64
 
 const (
 	LOG_LEVEL_ERROR LOG_LEVEL = iota
@@ -38,6 +41,15 @@ const (
 	ENTRY_EVENT_NONE
 	ENTRY_EVENT_FOCUS
 	ENTRY_EVENT_UN_FOCUS
+	ENTRY_EVENT_UNDO
+	ENTRY_EVENT_REDO
+	ENTRY_EVENT_SCROLL
+	ENTRY_EVENT_DROP
+
+	CLICK_KIND_CLICK        CLICK_KIND = iota // Left button (or Space / Return on a focused widget)
+	CLICK_KIND_RIGHT                          // Right button
+	CLICK_KIND_MIDDLE                         // Middle button
+	CLICK_KIND_DOUBLE_CLICK                   // Left button second click. The CLICK_KIND_CLICK listeners are called as well
 
 	WIDGET_STYLE_DRAW_NONE          STATE_BITS = 0b0000000000000001
 	WIDGET_STYLE_DRAW_BORDER        STATE_BITS = 0b0000000000000010
@@ -50,6 +62,7 @@ const (
 	WIDGET_STATE_NOT_FOCUSED STATE_BITS = 0b0000000001000000
 	WIDGET_STATE_NOT_ERROR   STATE_BITS = 0b0000000010000000
 	WIDGET_STATE_NOT_CLICKED STATE_BITS = 0b0000000100000000
+	WIDGET_STATE_HOVER       STATE_BITS = 0b0000001000000000 // Mouse is over the widget. Set by SDL_WidgetGroup
 	WIDGET_STATE_ENA_SET     STATE_BITS = 0b0000000100110000 // Enabled, visible, and not-clicked
 	WIDGET_STATE_MASK        STATE_BITS = 0b0000000111110000 // Clear state AND mask. Retains style.
 
@@ -58,12 +71,17 @@ const (
 
 var TEXTURE_CACHE_TEXT_PREF = "TxCaPr987"
 
+var listenerHandles uint64 // Last SDL_ListenerHandle issued. Click listeners and shortcuts
+
 type SDL_Widget interface {
 	Draw(*sdl.Renderer, *ttf.Font) error
 	Scale(float32)
 	Inside(int32, int32) (SDL_Widget, bool) // Base
 	Click(*SDL_MouseData) bool
+	Release(*SDL_MouseData) bool                       // Base
 	SetOnClick(func(string, int32, int32, int32) bool) // Base
+	SetOnMouseEnter(func(SDL_Widget))                  // Base
+	SetOnMouseLeave(func(SDL_Widget))                  // Base
 	KeyPress(c int, ctrl, down bool) bool
 	SetWidgetId(int32)  // Base
 	GetWidgetId() int32 // Base
@@ -93,13 +111,21 @@ type SDL_Widget interface {
 	IsVisible() bool // Base
 	SetClicked(bool) // Base
 	IsClicked() bool // Base
+	IsPressed() bool // Base
 	SetEnabled(bool) // Base
 	IsEnabled() bool // Base
 	SetError(bool)   // Base
 	IsError() bool   // Base
 	SetFocused(bool) // Base
 	IsFocused() bool // Base
+	SetHover(bool)   // Base
+	IsHover() bool   // Base
 	CanFocus() bool
+	SetCanFocus(bool)   // Base
+	SetTabIndex(int32)  // Base
+	GetTabIndex() int32 // Base
+	SetMnemonic(rune)   // Base
+	GetMnemonic() rune  // Base
 
 	SetLog(func(LOG_LEVEL, string))
 	Log(LOG_LEVEL, string)
@@ -113,6 +139,31 @@ type SDL_CanSelectText interface {
 	GetSelectedText() string
 }
 
+/*
+Widgets that accept text from sdl.TextInputEvent and IME composition from sdl.TextEditingEvent
+*/
+type SDL_TextInput interface {
+	TextInput(string) bool
+	TextEditing(string, int32, int32) bool
+}
+
+/*
+Widgets that react to the mouse wheel. dx and dy are wheel steps. dy > 0 is away from the user.
+precise is dy with fractions from high resolution wheels and touch pads.
+Return false to pass the scroll on to the container the widget is in.
+*/
+type SDL_Scrollable interface {
+	Scroll(dx, dy int32, precise float32) bool
+}
+
+/*
+Widgets that zoom with a touch pinch. factor > 1 is fingers moving apart. x,y is the centre of the pinch.
+Return false to pass the zoom on to the container the widget is in.
+*/
+type SDL_Zoomable interface {
+	Zoom(factor float32, x, y int32) bool
+}
+
 type SDL_TextWidget interface {
 	SetText(text string)
 	GetText() string
@@ -142,17 +193,29 @@ type SDL_LinkedWidget struct {
 }
 
 type SDL_WidgetBase struct {
-	x, y, w, h   int32
-	widgetId     int32
-	instance     SDL_Widget
-	deBounce     int
-	onClick      func(string, int32, int32, int32) bool
-	background   *sdl.Color
-	foreground   *sdl.Color
-	borderColour *sdl.Color
-	state        STATE_BITS
-	canfocus     bool
-	log          func(LOG_LEVEL, string)
+	x, y, w, h        int32
+	widgetId          int32
+	instance          SDL_Widget
+	deBounce          int
+	pressed           bool   // The mouse went down on the widget and has not been released
+	pressedOn         bool   // pressed and the mouse is on the widget. Not the clicked state so it stays enabled
+	releaseAt         uint64 // Ticks when the clicked (pressed) state shown after a click ends
+	onClick           func(string, int32, int32, int32) bool
+	onMouseEnter      func(SDL_Widget)
+	onMouseLeave      func(SDL_Widget)
+	clickListeners    []*sdl_ClickListener
+	clickListenerLock sync.Mutex
+	background        *sdl.Color
+	foreground        *sdl.Color
+	borderColour      *sdl.Color
+	state             STATE_BITS
+	canfocus          bool
+	tabIndex          int32
+	mnemonic          rune // Alt+mnemonic activates the widget. Underlined in the text if it has text
+	dragSource        func(int32, int32) *SDL_DragPayload
+	acceptDrop        func(*SDL_DragPayload) bool
+	onDrop            func(*SDL_DragPayload, int32, int32) bool
+	log               func(LOG_LEVEL, string)
 }
 
 /****************************************************************************************
@@ -186,25 +249,178 @@ func (b *SDL_WidgetBase) String() string {
 	return fmt.Sprintf("ID:%d", b.widgetId)
 }
 
+/*
+If the mouse is down (md.IsDown) the widget is pressed and onClick is called by Release.
+While the mouse is dragged the widget only shows as pressed when the mouse is on it.
+Otherwise (for example Space on a focused widget) onClick is called now.
+*/
 func (b *SDL_WidgetBase) Click(md *SDL_MouseData) bool {
-	if b.IsEnabled() && b.onClick != nil {
-		if b.deBounce > 0 {
-			b.SetClicked(true)
-			defer func() {
-				time.Sleep(time.Millisecond * time.Duration(b.deBounce))
-				b.SetClicked(false)
-			}()
+	b.releaseClicked()
+	if b.pressed {
+		if md.IsDragging() {
+			b.pressedOn = isInsideRect(md.GetDraggingX(), md.GetDraggingY(), b.GetRect())
+		}
+		return true
+	}
+	if b.IsEnabled() && b.hasClickHandler(md) {
+		if md.IsDown() {
+			b.pressed = true
+			b.pressedOn = true
+			return true
+		}
+		b.holdClicked()
+		return b.fireClick(md)
+	}
+	return false
+}
+
+/*
+The mouse button was released after it went down on the widget. If it is still on the widget onClick is called.
+If it was moved off the widget the click is cancelled.
+*/
+func (b *SDL_WidgetBase) Release(md *SDL_MouseData) bool {
+	if !b.pressed {
+		return false
+	}
+	b.pressed = false
+	b.pressedOn = false
+	if !b.IsVisible() || !isInsideRect(md.GetX(), md.GetY(), b.GetRect()) {
+		return false
+	}
+	b.holdClicked()
+	return b.fireClick(md)
+}
+
+/*
+Call onClick and the listeners for the mouse button. Returns true if any of them returned true.
+onClick is only called for the left button (or a click that is not from the mouse).
+*/
+func (b *SDL_WidgetBase) fireClick(md *SDL_MouseData) bool {
+	ev := newClickEvent(b.instance, md)
+	kind := ev.GetKind()
+	used := false
+	if kind == CLICK_KIND_CLICK || kind == CLICK_KIND_DOUBLE_CLICK {
+		if b.onClick != nil {
+			used = b.onClick(b.String(), b.widgetId, md.x, md.y)
+		}
+	}
+	b.clickListenerLock.Lock()
+	listeners := make([]*sdl_ClickListener, len(b.clickListeners))
+	copy(listeners, b.clickListeners)
+	b.clickListenerLock.Unlock()
+	for _, l := range listeners {
+		if l.kind == kind || (l.kind == CLICK_KIND_CLICK && kind == CLICK_KIND_DOUBLE_CLICK) {
+			if l.listener(ev) {
+				used = true
+			}
+		}
+	}
+	return used
+}
+
+func (b *SDL_WidgetBase) hasClickHandler(md *SDL_MouseData) bool {
+	kind := clickKind(md)
+	if b.onClick != nil && (kind == CLICK_KIND_CLICK || kind == CLICK_KIND_DOUBLE_CLICK) {
+		return true
+	}
+	b.clickListenerLock.Lock()
+	defer b.clickListenerLock.Unlock()
+	for _, l := range b.clickListeners {
+		if l.kind == kind || (l.kind == CLICK_KIND_CLICK && kind == CLICK_KIND_DOUBLE_CLICK) {
+			return true
 		}
-		return b.onClick(b.String(), b.widgetId, md.x, md.y)
 	}
 	return false
 }
 
+/*
+Add a listener for a kind of click. Any number can be added. They are called in the order they were added,
+after onClick. Returns a handle to remove it with RemoveClickListener.
+*/
+func (b *SDL_WidgetBase) AddClickListener(kind CLICK_KIND, listener func(*SDL_ClickEvent) bool) SDL_ListenerHandle {
+	h := SDL_ListenerHandle(atomic.AddUint64(&listenerHandles, 1))
+	b.clickListenerLock.Lock()
+	defer b.clickListenerLock.Unlock()
+	b.clickListeners = append(b.clickListeners, &sdl_ClickListener{handle: h, kind: kind, listener: listener})
+	return h
+}
+
+func (b *SDL_WidgetBase) AddRightClickListener(listener func(*SDL_ClickEvent) bool) SDL_ListenerHandle {
+	return b.AddClickListener(CLICK_KIND_RIGHT, listener)
+}
+
+func (b *SDL_WidgetBase) AddMiddleClickListener(listener func(*SDL_ClickEvent) bool) SDL_ListenerHandle {
+	return b.AddClickListener(CLICK_KIND_MIDDLE, listener)
+}
+
+func (b *SDL_WidgetBase) AddDoubleClickListener(listener func(*SDL_ClickEvent) bool) SDL_ListenerHandle {
+	return b.AddClickListener(CLICK_KIND_DOUBLE_CLICK, listener)
+}
+
+/*
+Returns false if the listener was not found
+*/
+func (b *SDL_WidgetBase) RemoveClickListener(h SDL_ListenerHandle) bool {
+	b.clickListenerLock.Lock()
+	defer b.clickListenerLock.Unlock()
+	for i, l := range b.clickListeners {
+		if l.handle == h {
+			b.clickListeners = append(b.clickListeners[:i], b.clickListeners[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+/*
+After a click the widget shows clicked, and ignores clicks, for deBounce milli seconds.
+It is released by releaseClicked on a later Click or Draw.
+*/
+func (b *SDL_WidgetBase) holdClicked() {
+	if b.deBounce > 0 {
+		b.SetClicked(true)
+		b.releaseAt = sdl.GetTicks64() + uint64(b.deBounce)
+	} else {
+		b.SetClicked(false)
+		b.releaseAt = 0
+	}
+}
+
+func (b *SDL_WidgetBase) releaseClicked() {
+	if b.releaseAt > 0 && sdl.GetTicks64() >= b.releaseAt {
+		b.releaseAt = 0
+		b.SetClicked(false)
+	}
+}
+
 func (b *SDL_WidgetBase) SetOnClick(f func(string, int32, int32, int32) bool) {
 	b.onClick = f
 }
 
+/*
+Called with the widget when the mouse moves on to it
+*/
+func (b *SDL_WidgetBase) SetOnMouseEnter(f func(SDL_Widget)) {
+	b.onMouseEnter = f
+}
+
+/*
+Called with the widget when the mouse moves off it
+*/
+func (b *SDL_WidgetBase) SetOnMouseLeave(f func(SDL_Widget)) {
+	b.onMouseLeave = f
+}
+
+/*
+Space or Return on a focused widget clicks it, at its centre
+*/
 func (b *SDL_WidgetBase) KeyPress(c int, ctrl bool, down bool) bool {
+	if down && b.IsFocused() && b.instance != nil {
+		switch c {
+		case sdl.K_SPACE, sdl.K_RETURN, sdl.K_KP_ENTER:
+			return b.instance.Click(&SDL_MouseData{x: b.x + (b.w / 2), y: b.y + (b.h / 2), button: sdl.BUTTON_LEFT, clickCount: 1})
+		}
+	}
 	return false
 }
 
@@ -319,6 +535,31 @@ func (b *SDL_WidgetBase) SetError(v bool) {
 	}
 }
 
+/*
+Set by SDL_WidgetGroup when the mouse moves on to (true) or off (false) the widget.
+OnMouseEnter or OnMouseLeave is called if it changes.
+*/
+func (b *SDL_WidgetBase) SetHover(v bool) {
+	if v == b.IsHover() {
+		return
+	}
+	if v {
+		b.state = b.state | WIDGET_STATE_HOVER
+		if b.onMouseEnter != nil {
+			b.onMouseEnter(b.instance)
+		}
+	} else {
+		b.state = b.state & ^WIDGET_STATE_HOVER
+		if b.onMouseLeave != nil {
+			b.onMouseLeave(b.instance)
+		}
+	}
+}
+
+func (b *SDL_WidgetBase) IsHover() bool {
+	return (b.state & WIDGET_STATE_HOVER) == WIDGET_STATE_HOVER
+}
+
 func (b *SDL_WidgetBase) IsError() bool {
 	return (b.state & WIDGET_STATE_NOT_ERROR) == 0
 }
@@ -335,10 +576,59 @@ func (b *SDL_WidgetBase) IsClicked() bool {
 	return (b.state & WIDGET_STATE_NOT_CLICKED) == 0
 }
 
+/*
+True while the mouse is held down on the widget. Unlike IsClicked the widget is still enabled
+*/
+func (b *SDL_WidgetBase) IsPressed() bool {
+	return b.pressedOn
+}
+
 func (b *SDL_WidgetBase) CanFocus() bool {
 	return b.canfocus
 }
 
+func (b *SDL_WidgetBase) SetCanFocus(v bool) {
+	b.canfocus = v
+	if !v {
+		b.state = b.state | WIDGET_STATE_NOT_FOCUSED
+	}
+}
+
+/*
+Widgets with a tab index > 0 are visited by Tab first, lowest first. Then those with 0 in the order they were added.
+Widgets with a tab index < 0 are not visited by Tab but can still be focused by clicking them.
+*/
+func (b *SDL_WidgetBase) SetTabIndex(i int32) {
+	b.tabIndex = i
+}
+
+func (b *SDL_WidgetBase) GetTabIndex() int32 {
+	return b.tabIndex
+}
+
+/*
+Alt+m clicks the widget (and focuses it if it can be focused). The first m in the text is underlined.
+Case is ignored. 0 removes the mnemonic. See SDL_WidgetGroup.ShortcutConflicts for duplicates.
+*/
+func (b *SDL_WidgetBase) SetMnemonic(m rune) {
+	b.mnemonic = m
+}
+
+func (b *SDL_WidgetBase) GetMnemonic() rune {
+	return b.mnemonic
+}
+
+/*
+Draw a ring just outside the widget when it has the focus
+*/
+func (b *SDL_WidgetBase) drawFocusRing(renderer *sdl.Renderer) {
+	if b.IsFocused() {
+		c := GetResourceInstance().GetFocusRingColour()
+		renderer.SetDrawColor(c.R, c.G, c.B, c.A)
+		renderer.DrawRect(&sdl.Rect{X: b.x - 2, Y: b.y - 2, W: b.w + 4, H: b.h + 4})
+	}
+}
+
 func (b *SDL_WidgetBase) SetFocused(v bool) {
 	if b.IsEnabled() && b.CanFocus() && v {
 		b.state = b.state & ^WIDGET_STATE_NOT_FOCUSED
@@ -418,6 +708,10 @@ func (b *SDL_WidgetBase) getResourceColourStateIndex() STATE_COLOUR {
 		if b.IsFocused() {
 			return WIDGET_COLOUR_INDEX_FOCUS
 		}
+		// Only widgets that do something when clicked are highlighted
+		if b.IsHover() && (b.onClick != nil || b.canfocus) {
+			return WIDGET_COLOUR_INDEX_HOVER
+		}
 		return WIDGET_COLOUR_INDEX_ENABLED
 	}
 	return WIDGET_COLOUR_INDEX_DISABLE
@@ -459,6 +753,7 @@ func (b *SDL_WidgetBase) Inside(x, y int32) (SDL_Widget, bool) {
 type SDL_MouseData struct {
 	x, y, draggingX, draggingY int32
 	button                     uint8
+	modifiers                  uint16 // Keyboard modifiers (sdl.KMOD_*) held when the button went down
 	down                       bool
 	clickCount                 int
 	dragged                    bool
@@ -559,6 +854,10 @@ func (md *SDL_MouseData) GetButtons() uint8 {
 	return md.button
 }
 
+func (md *SDL_MouseData) GetModifiers() uint16 {
+	return md.modifiers
+}
+
 func (md *SDL_MouseData) setXY(x, y int32) {
 	if md.dragging {
 		md.draggingX = x
@@ -569,6 +868,116 @@ func (md *SDL_MouseData) setXY(x, y int32) {
 	}
 }
 
+/****************************************************************************************
+* SDL_ClickEvent
+* Passed to click listeners. See SDL_WidgetBase.AddClickListener
+**/
+type SDL_ClickEvent struct {
+	widget    SDL_Widget
+	x, y      int32
+	button    uint8
+	clicks    int
+	modifiers uint16
+}
+
+type sdl_ClickListener struct {
+	handle   SDL_ListenerHandle
+	kind     CLICK_KIND
+	listener func(*SDL_ClickEvent) bool
+}
+
+func newClickEvent(w SDL_Widget, md *SDL_MouseData) *SDL_ClickEvent {
+	button := md.button
+	if button == 0 {
+		button = sdl.BUTTON_LEFT
+	}
+	clicks := md.clickCount
+	if clicks < 1 {
+		clicks = 1
+	}
+	return &SDL_ClickEvent{widget: w, x: md.x, y: md.y, button: button, clicks: clicks, modifiers: md.modifiers}
+}
+
+func clickKind(md *SDL_MouseData) CLICK_KIND {
+	switch md.button {
+	case sdl.BUTTON_RIGHT:
+		return CLICK_KIND_RIGHT
+	case sdl.BUTTON_MIDDLE:
+		return CLICK_KIND_MIDDLE
+	}
+	if md.clickCount == 2 {
+		return CLICK_KIND_DOUBLE_CLICK
+	}
+	return CLICK_KIND_CLICK
+}
+
+func (ev *SDL_ClickEvent) String() string {
+	return fmt.Sprintf("ID:%d x:%d y:%d Btn:%d Clicks:%d Mod:%X", ev.GetWidgetId(), ev.x, ev.y, ev.button, ev.clicks, ev.modifiers)
+}
+
+func (ev *SDL_ClickEvent) GetKind() CLICK_KIND {
+	return clickKind(&SDL_MouseData{button: ev.button, clickCount: ev.clicks})
+}
+
+func (ev *SDL_ClickEvent) GetWidget() SDL_Widget {
+	return ev.widget
+}
+
+func (ev *SDL_ClickEvent) GetWidgetId() int32 {
+	if ev.widget == nil {
+		return 0
+	}
+	return ev.widget.GetWidgetId()
+}
+
+/*
+The widget String(). The same as the first onClick parameter
+*/
+func (ev *SDL_ClickEvent) GetText() string {
+	if ev.widget == nil {
+		return ""
+	}
+	return ev.widget.String()
+}
+
+func (ev *SDL_ClickEvent) GetX() int32 {
+	return ev.x
+}
+
+func (ev *SDL_ClickEvent) GetY() int32 {
+	return ev.y
+}
+
+/*
+sdl.BUTTON_LEFT, sdl.BUTTON_MIDDLE or sdl.BUTTON_RIGHT. Clicks from the keyboard are sdl.BUTTON_LEFT
+*/
+func (ev *SDL_ClickEvent) GetButton() uint8 {
+	return ev.button
+}
+
+func (ev *SDL_ClickEvent) GetClickCount() int {
+	return ev.clicks
+}
+
+/*
+The sdl.KMOD_* keyboard modifiers held when the mouse button went down
+*/
+func (ev *SDL_ClickEvent) GetModifiers() uint16 {
+	return ev.modifiers
+}
+
+func (ev *SDL_ClickEvent) IsCtrlDown() bool {
+	return ev.modifiers&sdl.KMOD_CTRL != 0
+}
+
+func (ev *SDL_ClickEvent) IsShiftDown() bool {
+	return ev.modifiers&sdl.KMOD_SHIFT != 0
+}
+
+func (ev *SDL_ClickEvent) IsAltDown() bool {
+	return ev.modifiers&sdl.KMOD_ALT != 0
+}
+
 /****************************************************************************************
 * Utilities
 * getCachedTextWidgetEntry Returns cached texture data